@@ -0,0 +1,103 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	_ "github.com/joho/godotenv/autoload"
+
+	"tsumitan/internal/database"
+	"tsumitan/internal/models"
+	"tsumitan/internal/scheduler"
+)
+
+// fsrs-optimize fits personalised FSRS parameters from each user's review log.
+// Cloud Scheduler などから定期的に実行することを想定している
+func main() {
+	userID := flag.String("user", "", "optimise only this user (default: every user with enough reviews)")
+	iterations := flag.Int("iterations", 200, "number of optimisation steps per user")
+	flag.Parse()
+
+	if name := os.Getenv("REVIEW_SCHEDULER"); name != "fsrs" {
+		log.Printf("REVIEW_SCHEDULER is %q: users fitted by this run will be scheduled with FSRS", name)
+	}
+
+	db := database.New()
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.Printf("Failed to close database: %v", err)
+		}
+	}()
+
+	if err := db.Migrate(); err != nil {
+		log.Fatalf("Failed to migrate database: %v", err)
+	}
+
+	userIDs := []string{*userID}
+	if *userID == "" {
+		ids, err := db.ReviewedUserIDs(scheduler.MinFSRSSamples)
+		if err != nil {
+			log.Fatalf("Failed to list users: %v", err)
+		}
+		userIDs = ids
+	}
+
+	fitted := 0
+	for _, id := range userIDs {
+		if err := optimizeUser(db, id, *iterations); err != nil {
+			log.Printf("Skipped user %s: %v", id, err)
+			continue
+		}
+		fitted++
+	}
+
+	log.Printf("FSRS optimisation finished: %d/%d users fitted", fitted, len(userIDs))
+}
+
+func optimizeUser(db database.Service, userID string, iterations int) error {
+	events, err := db.UserReviewEvents(userID)
+	if err != nil {
+		return err
+	}
+
+	// 単語ごとの復習履歴にまとめる（イベントは言語・単語・日時順に並んでいる）
+	var histories [][]scheduler.ReviewLog
+	for i, event := range events {
+		grade, err := scheduler.ParseGrade(event.Grade)
+		if err != nil {
+			return err
+		}
+		if i == 0 || events[i-1].Word != event.Word || events[i-1].Lang != event.Lang {
+			histories = append(histories, nil)
+		}
+		last := len(histories) - 1
+		histories[last] = append(histories[last], scheduler.ReviewLog{
+			Grade:      grade,
+			ReviewedAt: event.ReviewedAt,
+		})
+	}
+
+	initial := scheduler.DefaultFSRSWeights
+	if params, err := db.GetFSRSParameters(userID); err == nil && params != nil {
+		if weights, err := scheduler.FSRSWeightsFromSlice(params.Weights); err == nil {
+			initial = weights
+		}
+	}
+
+	fit, err := scheduler.FitFSRS(histories, initial, iterations)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Fitted FSRS parameters for user %s: %d reviews, loss %.4f", userID, fit.Samples, fit.Loss)
+
+	return db.SaveFSRSParameters(&models.FSRSParameters{
+		UserID:      userID,
+		Weights:     fit.Weights[:],
+		ReviewCount: fit.Samples,
+		Loss:        fit.Loss,
+		FittedAt:    time.Now(),
+	})
+}
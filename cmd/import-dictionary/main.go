@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"log"
+
+	_ "github.com/joho/godotenv/autoload"
+
+	"tsumitan/internal/database"
+	"tsumitan/internal/dictionary"
+)
+
+// dataset is one direction imported from a dictionary file.
+type dataset struct {
+	direction dictionary.Direction
+	format    string
+}
+
+// EDICT は和英辞書なので、和英としてそのまま、英和として訳語から逆引きできる形の両方で取り込む
+var datasets = map[string][]dataset{
+	"ejdict": {{dictionary.EnJa, "ejdict"}},
+	"edict":  {{dictionary.EnJa, "edict"}, {dictionary.JaEn, "edict-jaen"}},
+}
+
+// import-dictionary loads an open dictionary dataset into the offline dictionary table.
+// 同じ source のエントリは置き換えられる
+func main() {
+	file := flag.String("file", "", "path to the UTF-8 dictionary file")
+	format := flag.String("format", "ejdict", "dictionary format: ejdict or edict")
+	source := flag.String("source", "", "name recorded with the entries (default: the format)")
+	flag.Parse()
+
+	if *file == "" {
+		log.Fatal("-file is required")
+	}
+	if *source == "" {
+		*source = *format
+	}
+	sets, ok := datasets[*format]
+	if !ok {
+		log.Fatalf("Unknown dictionary format: %s", *format)
+	}
+
+	db := database.New()
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.Printf("Failed to close database: %v", err)
+		}
+	}()
+
+	if err := db.Migrate(); err != nil {
+		log.Fatalf("Failed to migrate database: %v", err)
+	}
+
+	for _, set := range sets {
+		entries, err := dictionary.LoadFile(*file, set.format)
+		if err != nil {
+			log.Fatalf("Failed to load dictionary: %v", err)
+		}
+		log.Printf("Loaded %d %s headwords from %s", len(entries), set.direction, *file)
+
+		if err := db.ReplaceLexiconEntries(*source, set.direction, entries); err != nil {
+			log.Fatalf("Failed to import dictionary: %v", err)
+		}
+
+		log.Printf("Imported %d %s headwords as %s", len(entries), set.direction, *source)
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"log"
+
+	_ "github.com/joho/godotenv/autoload"
+
+	"tsumitan/internal/corpus"
+	"tsumitan/internal/database"
+	"tsumitan/internal/dictionary"
+)
+
+// import-examples loads a bilingual sentence corpus into the example sentence table
+// and indexes the headwords of each sentence. 同じ source・言語ペアの例文は置き換えられる
+func main() {
+	file := flag.String("file", "", "path to the UTF-8 TSV file of sentence pairs (Tatoeba export or text<TAB>translation)")
+	lang := flag.String("lang", string(dictionary.DefaultLang), "language pair of the sentences and their translations, e.g. en-ja")
+	source := flag.String("source", "tatoeba", "name recorded with the sentences")
+	flag.Parse()
+
+	if *file == "" {
+		log.Fatal("-file is required")
+	}
+	dir, ok := dictionary.ParseLang(*lang)
+	if !ok {
+		log.Fatalf("Invalid language pair: %s", *lang)
+	}
+
+	pairs, err := corpus.LoadFile(*file)
+	if err != nil {
+		log.Fatalf("Failed to load corpus: %v", err)
+	}
+	log.Printf("Loaded %d %s sentence pairs from %s", len(pairs), dir, *file)
+
+	db := database.New()
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.Printf("Failed to close database: %v", err)
+		}
+	}()
+
+	if err := db.Migrate(); err != nil {
+		log.Fatalf("Failed to migrate database: %v", err)
+	}
+
+	if err := db.ReplaceExampleSentences(*source, dir, pairs); err != nil {
+		log.Fatalf("Failed to import corpus: %v", err)
+	}
+
+	log.Printf("Imported %d %s sentence pairs as %s", len(pairs), dir, *source)
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"flag"
+	"log"
+
+	_ "github.com/joho/godotenv/autoload"
+
+	"tsumitan/internal/database"
+	"tsumitan/internal/wordnet"
+)
+
+// import-wordnet loads the synonyms, antonyms, hypernyms and derivationally related forms
+// of a WordNet database into the word relation table. 同じ source の関係は置き換えられる
+func main() {
+	dir := flag.String("dir", "", "path to the WordNet dict directory containing data.noun, data.verb, data.adj and data.adv")
+	source := flag.String("source", "wordnet", "name recorded with the relations")
+	flag.Parse()
+
+	if *dir == "" {
+		log.Fatal("-dir is required")
+	}
+
+	links, err := wordnet.LoadDir(*dir)
+	if err != nil {
+		log.Fatalf("Failed to load WordNet: %v", err)
+	}
+	log.Printf("Loaded %d word relations from %s", len(links), *dir)
+
+	db := database.New()
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.Printf("Failed to close database: %v", err)
+		}
+	}()
+
+	if err := db.Migrate(); err != nil {
+		log.Fatalf("Failed to migrate database: %v", err)
+	}
+
+	if err := db.ReplaceWordRelations(*source, links); err != nil {
+		log.Fatalf("Failed to import WordNet: %v", err)
+	}
+
+	log.Printf("Imported %d word relations as %s", len(links), *source)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"flag"
+	"log"
+
+	_ "github.com/joho/godotenv/autoload"
+
+	"tsumitan/internal/database"
+	"tsumitan/internal/dictionary"
+	"tsumitan/internal/normalize"
+)
+
+// group is the set of a user's words that normalise to the same headword.
+type group struct {
+	userID   string
+	lang     string
+	word     string
+	variants []string
+}
+
+// normalize-words merges words recorded before normalisation was introduced,
+// e.g. "Running", "running" and "ran" into "run". 一度だけ実行することを想定している
+func main() {
+	dryRun := flag.Bool("dry-run", false, "only print the words that would be merged")
+	flag.Parse()
+
+	db := database.New()
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.Printf("Failed to close database: %v", err)
+		}
+	}()
+
+	if err := db.Migrate(); err != nil {
+		log.Fatalf("Failed to migrate database: %v", err)
+	}
+
+	words, err := db.AllWords()
+	if err != nil {
+		log.Fatalf("Failed to list words: %v", err)
+	}
+
+	// ユーザー・言語ペア・正規化後の単語ごとにまとめる
+	var groups []*group
+	index := map[[3]string]*group{}
+	for _, w := range words {
+		headword := normalize.Word(w.Word, dictionary.Direction(w.Lang).Source())
+		key := [3]string{w.UserID, w.Lang, headword}
+		g, ok := index[key]
+		if !ok {
+			g = &group{userID: w.UserID, lang: w.Lang, word: headword}
+			index[key] = g
+			groups = append(groups, g)
+		}
+		if w.Word != headword {
+			g.variants = append(g.variants, w.Word)
+		}
+	}
+
+	merged := 0
+	for _, g := range groups {
+		if len(g.variants) == 0 {
+			continue
+		}
+		log.Printf("User %s (%s): %q → %s", g.userID, g.lang, g.variants, g.word)
+		if *dryRun {
+			continue
+		}
+		if err := db.MergeWords(g.userID, g.lang, g.word, g.variants); err != nil {
+			log.Fatalf("Failed to merge words: %v", err)
+		}
+		merged++
+	}
+
+	log.Printf("Word normalisation finished: %d headwords merged", merged)
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"time"
+
+	_ "github.com/joho/godotenv/autoload"
+
+	"tsumitan/internal/database"
+)
+
+// purge-trash permanently deletes the words that have been in the trash for longer than -days,
+// together with their deck and tag links and their review and search logs.
+// Cloud Scheduler などから定期的に実行することを想定している
+func main() {
+	days := flag.Int("days", 30, "delete words that have been in the trash for more than this many days")
+	flag.Parse()
+
+	if *days < 0 {
+		log.Fatal("-days must not be negative")
+	}
+
+	db := database.New()
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.Printf("Failed to close database: %v", err)
+		}
+	}()
+
+	if err := db.Migrate(); err != nil {
+		log.Fatalf("Failed to migrate database: %v", err)
+	}
+
+	before := time.Now().AddDate(0, 0, -*days)
+	purged, err := db.PurgeTrash(before)
+	if err != nil {
+		log.Fatalf("Failed to purge trash: %v", err)
+	}
+
+	log.Printf("Purged %d words moved to the trash before %s", purged, before.Format(time.RFC3339))
+}
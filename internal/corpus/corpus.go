@@ -0,0 +1,89 @@
+// Package corpus reads bilingual sentence corpora such as the Tatoeba sentence pairs
+// and splits sentences into the headwords they contain.
+package corpus
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+
+	"tsumitan/internal/normalize"
+)
+
+// Pair is a sentence and its translation.
+type Pair struct {
+	Text        string
+	Translation string
+}
+
+// LoadTSV reads sentence pairs from a tab separated file. Lines are either Tatoeba's
+// "id<TAB>text<TAB>id<TAB>translation" export or plain "text<TAB>translation".
+// 同じ文に複数の訳がある場合は最初の訳を使う
+func LoadTSV(r io.Reader) ([]Pair, error) {
+	var pairs []Pair
+	seen := map[string]bool{}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), "\t")
+		var pair Pair
+		switch len(fields) {
+		case 2:
+			pair = Pair{Text: fields[0], Translation: fields[1]}
+		case 4:
+			pair = Pair{Text: fields[1], Translation: fields[3]}
+		default:
+			continue
+		}
+		pair.Text = strings.TrimSpace(pair.Text)
+		pair.Translation = strings.TrimSpace(pair.Translation)
+		if pair.Text == "" || pair.Translation == "" || seen[pair.Text] {
+			continue
+		}
+		seen[pair.Text] = true
+		pairs = append(pairs, pair)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read corpus file: %w", err)
+	}
+
+	return pairs, nil
+}
+
+// LoadFile reads the sentence pairs of the TSV file at path.
+func LoadFile(path string) ([]Pair, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open corpus file: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	return LoadTSV(f)
+}
+
+// Tokens returns the distinct headwords of text in language, an ISO 639-1 code such as "en",
+// so that "She ran home." yields "she", "run" and "home".
+// 単語を空白で区切らない言語（日本語など）の文は分割できない
+func Tokens(text, language string) []string {
+	var tokens []string
+	seen := map[string]bool{}
+
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\'' && r != '’'
+	})
+	for _, word := range words {
+		word = normalize.Word(strings.Trim(word, "'’"), language)
+		if word == "" || seen[word] {
+			continue
+		}
+		seen[word] = true
+		tokens = append(tokens, word)
+	}
+	return tokens
+}
@@ -2,16 +2,25 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
+	"tsumitan/internal/corpus"
+	"tsumitan/internal/dictionary"
 	"tsumitan/internal/models"
+	"tsumitan/internal/normalize"
+	"tsumitan/internal/scheduler"
+	"tsumitan/internal/wordlist"
+	"tsumitan/internal/wordnet"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // Service represents a service that interacts with a database.
@@ -20,11 +29,182 @@ type Service interface {
 	Close() error
 	Migrate() error
 	// Word operations
-	CreateOrUpdateWordSearch(userID, word string) error
-	PendingWordSearch(userID string) ([]models.Word, error)
-	UpdateWordReview(userID, word string) error
-	ReviewedWordSearch(userID string) ([]models.Word, error)
-	GetWordInfo(userID, word string) (*models.Word, error)
+	// lang is the language pair of the word, e.g. "en-ja"; list operations accept "" for every pair
+	CreateOrUpdateWordSearch(userID, lang, word string, search SearchInput) error
+	PendingWordSearch(userID, lang string, now time.Time, opts PendingOptions) ([]models.Word, error)
+	CountDueWords(userID, lang string, from, to time.Time) (int64, error)
+	UpdateWordReview(userID, lang, word string, sched scheduler.Scheduler, review ReviewInput) error
+	ReviewedWordSearch(userID, lang string, filter WordFilter) ([]models.Word, error)
+	GetWordInfo(userID, lang, word string) (*models.Word, error)
+	UpdateWordNotes(userID, lang, word string, notes NotesInput) (*models.Word, error)
+	WordsWithPrefix(userID, lang, prefix string, limit int) ([]string, error)
+	WordReviewEvents(userID, lang, word string) ([]models.ReviewEvent, error)
+	WordSearchEvents(userID, lang, word string) ([]models.SearchEvent, error)
+	AllWords() ([]models.Word, error)
+	MergeWords(userID, lang, word string, variants []string) error
+	SavedWords(userID, lang string, words []string) (map[string]bool, error)
+	// Trash and archive operations
+	DeleteWord(userID, lang, word string) (bool, error)
+	RestoreWord(userID, lang, word string) (*models.Word, error)
+	TrashedWords(userID, lang string) ([]models.Word, error)
+	PurgeTrash(before time.Time) (int64, error)
+	SetWordArchived(userID, lang, word string, archived bool, now time.Time) (*models.Word, error)
+	ArchivedWords(userID, lang string) ([]models.Word, error)
+	// Review queue flag operations
+	SetWordFlag(userID, lang, word string, flag WordFlag, value any) (*models.Word, error)
+	SuspendedWords(userID, lang string) ([]models.Word, error)
+	// Review session operations
+	CreateReviewSession(userID, lang string, now time.Time, opts PendingOptions, size int) (*ReviewSessionInfo, error)
+	GetReviewSession(userID string, id uint) (*ReviewSessionInfo, error)
+	CurrentReviewSession(userID string) (*ReviewSessionInfo, error)
+	AnswerReviewSession(userID string, id uint, lang, word string, sched scheduler.Scheduler, review ReviewInput) error
+	FinishReviewSession(userID string, id uint, now time.Time) (*ReviewSessionInfo, error)
+	// Deck and tag operations
+	ListCollections(col Collection, userID string) ([]CollectionInfo, error)
+	GetCollection(col Collection, userID string, id uint) (*CollectionInfo, error)
+	CreateCollection(col Collection, userID string, input CollectionInput) (*CollectionInfo, error)
+	UpdateCollection(col Collection, userID string, id uint, input CollectionInput) (*CollectionInfo, error)
+	DeleteCollection(col Collection, userID string, id uint) (bool, error)
+	CollectionWords(col Collection, userID string, id uint) ([]models.Word, error)
+	AddCollectionWord(col Collection, userID string, id uint, lang, word string) error
+	RemoveCollectionWord(col Collection, userID string, id uint, lang, word string) error
+	// FSRS parameter operations
+	UserReviewEvents(userID string) ([]models.ReviewEvent, error)
+	ReviewedUserIDs(minReviews int) ([]string, error)
+	GetFSRSParameters(userID string) (*models.FSRSParameters, error)
+	SaveFSRSParameters(params *models.FSRSParameters) error
+	// Offline dictionary operations
+	LookupEntry(ctx context.Context, word string, dir dictionary.Direction) (string, bool, error)
+	ReplaceLexiconEntries(source string, dir dictionary.Direction, entries map[string]string) error
+	LexiconHeadwords() (map[dictionary.Direction][]string, error)
+	// Word relation operations
+	WordRelations(word string) ([]models.WordRelation, error)
+	ReplaceWordRelations(source string, links []wordnet.Link) error
+	// Example sentence operations
+	ExampleSentences(lang string, tokens []string, limit int) ([]models.ExampleSentence, error)
+	ReplaceExampleSentences(source string, lang dictionary.Direction, pairs []corpus.Pair) error
+	// Dictionary cache operations
+	GetDictionaryEntry(ctx context.Context, key string) (*models.DictionaryEntry, error)
+	SaveDictionaryEntry(ctx context.Context, entry *models.DictionaryEntry) error
+}
+
+// SearchInput describes where a word was looked up.
+type SearchInput struct {
+	SearchedAt time.Time
+	// SurfaceForm is the word as the user typed it
+	SurfaceForm string
+	SourceURL   string
+	SourceTitle string
+	// Sentence is the sentence the word appeared in
+	Sentence string
+}
+
+// PendingOrder is the order of the pending review queue.
+type PendingOrder string
+
+const (
+	OrderDue       PendingOrder = "due"       // 期限の古い順
+	OrderLevel     PendingOrder = "level"     // CEFR レベルの易しい順
+	OrderFrequency PendingOrder = "frequency" // 頻度の高い順
+)
+
+// PendingOptions filters and orders the pending review queue.
+type PendingOptions struct {
+	// Levels restricts the queue to words of these CEFR levels, all levels if empty
+	Levels []string
+	Order  PendingOrder
+	// StarredOnly restricts the queue to starred words
+	StarredOnly bool
+	WordFilter
+}
+
+// WordFilter restricts a word list to one deck and/or one tag. 0 means no restriction.
+type WordFilter struct {
+	DeckID uint
+	TagID  uint
+}
+
+// Collection is a kind of user-defined group of words. Decks and tags are stored in
+// tables of the same shape, so they share the operations of Service.
+type Collection struct {
+	table string // decks
+	links string // deck_words
+	key   string // deck_id
+}
+
+var (
+	Decks = Collection{table: "decks", links: "deck_words", key: "deck_id"}
+	Tags  = Collection{table: "tags", links: "word_tags", key: "tag_id"}
+)
+
+// ReviewSessionInfo is a review session with its cards in order.
+type ReviewSessionInfo struct {
+	models.ReviewSession
+	Cards []SessionCard
+}
+
+// SessionCard is a card of a review session with the current content of its word.
+type SessionCard struct {
+	models.ReviewSessionCard
+	Kind             string
+	Level            string
+	Notes            string
+	Mnemonic         string
+	CustomDefinition string
+}
+
+// Errors returned by AnswerReviewSession.
+var (
+	ErrSessionNotFound = errors.New("review session not found")
+	ErrSessionFinished = errors.New("review session already finished")
+	ErrCardNotFound    = errors.New("word not in review session")
+	ErrCardAnswered    = errors.New("word already answered in review session")
+)
+
+// WordFlag is a column of models.Word that shapes the review queue.
+type WordFlag string
+
+const (
+	FlagSuspended   WordFlag = "suspended"    // bool
+	FlagBuriedUntil WordFlag = "buried_until" // *time.Time
+	FlagStarred     WordFlag = "starred"      // bool
+)
+
+// ErrNameTaken is returned when the user already has a deck or tag of the same name.
+var ErrNameTaken = errors.New("name already in use")
+
+// CollectionInfo is a deck or tag with the number of words in it.
+type CollectionInfo struct {
+	ID          uint
+	Name        string
+	Description string
+	WordCount   int
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// CollectionInput is the user-editable content of a deck or tag.
+type CollectionInput struct {
+	Name        string
+	Description string
+}
+
+// NotesInput is the content written by the learner for a word. nil fields are left unchanged.
+type NotesInput struct {
+	// Notes is free-form Markdown
+	Notes            *string
+	Mnemonic         *string
+	CustomDefinition *string
+}
+
+// ReviewInput describes a single graded review answer.
+type ReviewInput struct {
+	Grade scheduler.Grade
+	// ResponseTimeMs is the time the learner took to answer, 0 when unknown
+	ResponseTimeMs int
+	ReviewedAt     time.Time
+	// Device identifies the client the review was made on
+	Device string
 }
 
 type service struct {
@@ -127,50 +307,219 @@ func (s *service) Close() error {
 	return sqlDB.Close()
 }
 
-// Migrate performs database migration for the Word model.
+// Migrate performs database migration for all models.
 func (s *service) Migrate() error {
 	log.Println("Migrating database...")
-	err := s.db.AutoMigrate(&models.Word{})
+	if err := s.extendPrimaryKey(&models.LexiconEntry{}, "Direction", "headword", "direction", "source"); err != nil {
+		log.Printf("Database migration failed: %v", err)
+		return err
+	}
+	if err := s.extendPrimaryKey(&models.Word{}, "Lang", "user_id", "word", "lang"); err != nil {
+		log.Printf("Database migration failed: %v", err)
+		return err
+	}
+	err := s.db.AutoMigrate(&models.Word{}, &models.ReviewEvent{}, &models.SearchEvent{}, &models.FSRSParameters{}, &models.LexiconEntry{}, &models.DictionaryEntry{}, &models.WordRelation{}, &models.ExampleSentence{}, &models.ExampleToken{}, &models.Deck{}, &models.DeckWord{}, &models.Tag{}, &models.WordTag{}, &models.ReviewSession{}, &models.ReviewSessionCard{})
 	if err != nil {
 		log.Printf("Database migration failed: %v", err)
 		return err
 	}
+	// 既存の単語は復習期限が未設定なので、作成日時を期限として埋める
+	if err := s.db.Model(&models.Word{}).Where("due_at IS NULL").Update("due_at", gorm.Expr("created_at")).Error; err != nil {
+		log.Printf("Database migration failed: %v", err)
+		return err
+	}
+	if err := s.classifyPhrases(); err != nil {
+		log.Printf("Database migration failed: %v", err)
+		return err
+	}
+	if err := s.estimateLevels(); err != nil {
+		log.Printf("Database migration failed: %v", err)
+		return err
+	}
 	log.Println("Database migration completed.")
 	return nil
 }
 
-// CreateOrUpdateWordSearch creates a new word record or increments search_count if it already exists
-func (s *service) CreateOrUpdateWordSearch(userID, word string) error {
-	var existingWord models.Word
+// extendPrimaryKey adds column to an existing table and rebuilds its primary key from columns.
+// AutoMigrate はカラムを追加できるが主キーは変更しないため、既存のテーブルはここで移行する
+func (s *service) extendPrimaryKey(model any, column string, columns ...string) error {
+	migrator := s.db.Migrator()
+	if !migrator.HasTable(model) || migrator.HasColumn(model, column) {
+		return nil
+	}
 
-	// Try to find existing record
-	result := s.db.Where("user_id = ? AND word = ?", userID, word).First(&existingWord)
+	stmt := &gorm.Statement{DB: s.db}
+	if err := stmt.Parse(model); err != nil {
+		return err
+	}
+	table := stmt.Schema.Table
 
-	if result.Error != nil {
-		// Check if it's a "record not found" error using GORM's errors
-		if result.Error == gorm.ErrRecordNotFound {
+	log.Printf("Adding %s to the primary key of %s...", column, table)
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Migrator().AddColumn(model, column); err != nil {
+			return err
+		}
+		return tx.Exec(fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s_pkey, ADD PRIMARY KEY (%s)",
+			table, table, strings.Join(columns, ", "))).Error
+	})
+}
+
+// classifyPhrases sets the kind of multi-word entries recorded before kinds were introduced.
+func (s *service) classifyPhrases() error {
+	var words []models.Word
+	if err := s.db.Where("kind = ? AND word LIKE ?", string(normalize.KindWord), "% %").Find(&words).Error; err != nil {
+		return err
+	}
+
+	for _, w := range words {
+		kind := normalize.KindOf(w.Word, dictionary.Direction(w.Lang).Source())
+		if err := s.db.Model(&w).Update("kind", string(kind)).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// estimateLevels sets the frequency rank and CEFR level of English words recorded without them.
+func (s *service) estimateLevels() error {
+	// 以前はリストにない単語を C2 と推定していたため、不明に戻す
+	if err := s.db.Model(&models.Word{}).Where("level = 'C2' AND frequency_rank = 0").Update("level", "").Error; err != nil {
+		return err
+	}
+
+	var words []models.Word
+	if err := s.db.Where("level = '' AND lang LIKE 'en-%' AND word NOT LIKE '% %'").Find(&words).Error; err != nil {
+		return err
+	}
+
+	for _, w := range words {
+		classify(&w)
+		// リストにない単語はレベル不明のまま
+		if w.Level == "" {
+			continue
+		}
+		if err := s.db.Model(&w).Updates(map[string]any{
+			"frequency_rank": w.FrequencyRank,
+			"level":          w.Level,
+		}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// classify sets the kind, frequency rank and CEFR level of w from its word and language pair.
+// 頻度順位とレベルは英語の単語のみ推定する
+func classify(w *models.Word) {
+	language := dictionary.Direction(w.Lang).Source()
+	w.Kind = string(normalize.KindOf(w.Word, language))
+	w.FrequencyRank = 0
+	w.Level = ""
+	if language == "en" {
+		w.FrequencyRank = wordlist.FrequencyRank(w.Word)
+		w.Level = wordlist.Level(w.Word)
+	}
+}
+
+// langScope restricts a query to one language pair, or to every pair when lang is empty.
+func langScope(lang string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if lang == "" {
+			return db
+		}
+		return db.Where("lang = ?", lang)
+	}
+}
+
+// filterScope restricts a query on words to the deck and tag of filter.
+func filterScope(filter WordFilter) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Scopes(collectionScope(Decks, filter.DeckID), collectionScope(Tags, filter.TagID))
+	}
+}
+
+// collectionScope restricts a query on words to the words in the deck or tag id, or to every word when id is 0.
+func collectionScope(col Collection, id uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if id == 0 {
+			return db
+		}
+		return db.Where(fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM %s AS l WHERE l.%s = ? AND l.user_id = words.user_id AND l.word = words.word AND l.lang = words.lang)",
+			col.links, col.key), id)
+	}
+}
+
+// CreateOrUpdateWordSearch creates a new word record or increments search_count if it already exists.
+// Every call is also logged as a SearchEvent in the same transaction.
+func (s *service) CreateOrUpdateWordSearch(userID, lang, word string, search SearchInput) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var existingWord models.Word
+
+		// Try to find existing record
+		// ゴミ箱にある単語も探し、もう一度検索されたら元に戻す
+		result := tx.Unscoped().Where("user_id = ? AND lang = ? AND word = ?", userID, lang, word).First(&existingWord)
+
+		if result.Error != nil {
+			// Check if it's a "record not found" error using GORM's errors
+			if result.Error != gorm.ErrRecordNotFound {
+				// Other error occurred
+				return result.Error
+			}
 			// Create new record
 			newWord := models.Word{
 				UserID:      userID,
 				Word:        word,
+				Lang:        lang,
 				SearchCount: 1,
 				ReviewCount: 0,
+				EaseFactor:  scheduler.DefaultEaseFactor,
+				// 新しい単語はすぐに復習対象にする
+				DueAt: search.SearchedAt,
+			}
+			classify(&newWord)
+			if err := tx.Create(&newWord).Error; err != nil {
+				return err
+			}
+		} else {
+			// Update existing record
+			if err := tx.Unscoped().Model(&existingWord).Updates(map[string]any{
+				"search_count": existingWord.SearchCount + 1,
+				"deleted_at":   nil,
+			}).Error; err != nil {
+				return err
 			}
-			return s.db.Create(&newWord).Error
 		}
-		// Other error occurred
-		return result.Error
-	}
 
-	// Update existing record
-	return s.db.Model(&existingWord).Update("search_count", existingWord.SearchCount+1).Error
+		// 検索ログを記録
+		return tx.Create(&models.SearchEvent{
+			UserID:      userID,
+			Word:        word,
+			Lang:        lang,
+			SearchedAt:  search.SearchedAt,
+			SurfaceForm: search.SurfaceForm,
+			SourceURL:   search.SourceURL,
+			SourceTitle: search.SourceTitle,
+			Sentence:    search.Sentence,
+		}).Error
+	})
+}
+
+// pendingOrders maps each order of the pending queue onto ORDER BY clauses.
+// レベル・頻度が不明な単語は最後に並べる
+var pendingOrders = map[PendingOrder]string{
+	OrderDue:       "due_at",
+	OrderLevel:     "level = '', level, frequency_rank = 0, frequency_rank, due_at",
+	OrderFrequency: "frequency_rank = 0, frequency_rank, due_at",
 }
 
-func (s *service) PendingWordSearch(userID string) ([]models.Word, error) {
+// PendingWordSearch returns the words whose review is due at now, starred words first and then
+// oldest due first unless opts says otherwise. Archived, suspended and buried words are left out
+func (s *service) PendingWordSearch(userID, lang string, now time.Time, opts PendingOptions) ([]models.Word, error) {
 	var words []models.Word
 
-	// Query to fetch records where ReviewCount = 0
-	err := s.db.Where("user_id = ? AND review_count = ?", userID, 0).Find(&words).Error
+	// Query to fetch records whose due date has passed
+	err := s.pendingQuery(userID, lang, now, opts).Find(&words).Error
 	if err != nil {
 		log.Printf("Error fetching pending words for user %s: %v", userID, err)
 		return nil, err
@@ -179,11 +528,56 @@ func (s *service) PendingWordSearch(userID string) ([]models.Word, error) {
 	return words, nil
 }
 
-func (s *service) UpdateWordReview(userID, word string) error {
+// CountDueWords counts the words that come due after from and up to to, leaving out
+// archived and suspended words and words still buried at to
+func (s *service) CountDueWords(userID, lang string, from, to time.Time) (int64, error) {
+	var count int64
+
+	err := s.db.Model(&models.Word{}).Scopes(langScope(lang)).
+		Where("user_id = ? AND due_at > ? AND due_at <= ? AND archived_at IS NULL", userID, from, to).
+		Where("NOT suspended AND (buried_until IS NULL OR buried_until <= ?)", to).
+		Count(&count).Error
+	if err != nil {
+		log.Printf("Error counting due words for user %s: %v", userID, err)
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// pendingQuery selects the words in the review queue of PendingWordSearch, in order
+func (s *service) pendingQuery(userID, lang string, now time.Time, opts PendingOptions) *gorm.DB {
+	order, ok := pendingOrders[opts.Order]
+	if !ok {
+		order = pendingOrders[OrderDue]
+	}
+
+	query := s.db.Scopes(langScope(lang), filterScope(opts.WordFilter)).
+		Where("user_id = ? AND due_at <= ? AND archived_at IS NULL", userID, now).
+		Where("NOT suspended AND (buried_until IS NULL OR buried_until <= ?)", now)
+	if len(opts.Levels) > 0 {
+		query = query.Where("level IN ?", opts.Levels)
+	}
+	if opts.StarredOnly {
+		query = query.Where("starred")
+	}
+	return query.Order("starred DESC, " + order)
+}
+
+// UpdateWordReview records a graded review and reschedules the word with sched.
+// The counter update and the review event are written in the same transaction.
+func (s *service) UpdateWordReview(userID, lang, word string, sched scheduler.Scheduler, review ReviewInput) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return reviewWord(tx, userID, lang, word, sched, review)
+	})
+}
+
+// reviewWord records a graded review of a word and reschedules it within tx
+func reviewWord(tx *gorm.DB, userID, lang, word string, sched scheduler.Scheduler, review ReviewInput) error {
 	var reviewedWord models.Word
 
 	// Try to find existing record
-	result := s.db.Where("user_id = ? AND word = ?", userID, word).First(&reviewedWord)
+	result := tx.Where("user_id = ? AND lang = ? AND word = ?", userID, lang, word).First(&reviewedWord)
 
 	if result.Error != nil {
 		// Check if it's a "record not found" error using GORM's errors
@@ -194,16 +588,58 @@ func (s *service) UpdateWordReview(userID, word string) error {
 		return result.Error
 	}
 
+	next := sched.Schedule(scheduler.State{
+		EaseFactor:   reviewedWord.EaseFactor,
+		IntervalDays: reviewedWord.IntervalDays,
+		Repetitions:  reviewedWord.Repetitions,
+		DueAt:        reviewedWord.DueAt,
+		Stability:    reviewedWord.Stability,
+		Difficulty:   reviewedWord.Difficulty,
+		// 一度も復習していない場合はゼロ値のまま
+		LastReviewedAt: reviewedWord.LastReviewed,
+	}, review.Grade, review.ReviewedAt)
+
+	updates := map[string]any{
+		"review_count":          reviewedWord.ReviewCount + 1,
+		"last_reviewed":         review.ReviewedAt,
+		"last_response_time_ms": review.ResponseTimeMs,
+		"ease_factor":           next.EaseFactor,
+		"interval_days":         next.IntervalDays,
+		"repetitions":           next.Repetitions,
+		"due_at":                next.DueAt,
+		"stability":             next.Stability,
+		"difficulty":            next.Difficulty,
+	}
+	// 思い出せなかった回数は別に数える
+	if review.Grade == scheduler.GradeAgain {
+		updates["lapse_count"] = reviewedWord.LapseCount + 1
+	}
+
 	// Update existing record
-	return s.db.Model(&reviewedWord).Update("review_count", reviewedWord.ReviewCount+1).Error
+	if err := tx.Model(&reviewedWord).Updates(updates).Error; err != nil {
+		return err
+	}
+
+	// 復習ログを記録
+	return tx.Create(&models.ReviewEvent{
+		UserID:               userID,
+		Word:                 word,
+		Lang:                 lang,
+		ReviewedAt:           review.ReviewedAt,
+		Grade:                review.Grade.String(),
+		ResponseTimeMs:       review.ResponseTimeMs,
+		PreviousIntervalDays: reviewedWord.IntervalDays,
+		NewIntervalDays:      next.IntervalDays,
+		Device:               review.Device,
+	}).Error
 }
 
 // GetWordHandler retrieves a word record by user ID and word
-func (s *service) ReviewedWordSearch(userID string) ([]models.Word, error) {
+func (s *service) ReviewedWordSearch(userID, lang string, filter WordFilter) ([]models.Word, error) {
 	var words []models.Word
 
-	// Query to fetch records where ReviewCount = 0
-	err := s.db.Where("user_id = ? AND review_count > 0", userID).Find(&words).Error
+	// Query to fetch records where ReviewCount > 0
+	err := s.db.Scopes(langScope(lang), filterScope(filter)).Where("user_id = ? AND review_count > 0", userID).Find(&words).Error
 	if err != nil {
 		log.Printf("Error fetching reviewed words for user %s: %v", userID, err)
 		return nil, err
@@ -212,11 +648,11 @@ func (s *service) ReviewedWordSearch(userID string) ([]models.Word, error) {
 	return words, nil
 }
 
-func (s *service) GetWordInfo(userID, word string) (*models.Word, error) {
+func (s *service) GetWordInfo(userID, lang, word string) (*models.Word, error) {
 	var wordInfo models.Word
 
 	// Try to find existing record
-	result := s.db.Where("user_id = ? AND word = ?", userID, word).First(&wordInfo)
+	result := s.db.Where("user_id = ? AND lang = ? AND word = ?", userID, lang, word).First(&wordInfo)
 
 	if result.Error != nil {
 		// Check if it's a "record not found" error using GORM's errors
@@ -229,3 +665,968 @@ func (s *service) GetWordInfo(userID, word string) (*models.Word, error) {
 
 	return &wordInfo, nil
 }
+
+// UpdateWordNotes updates the notes, mnemonic and custom definition of a word and returns
+// the updated record, or nil if the user has not saved the word
+func (s *service) UpdateWordNotes(userID, lang, word string, notes NotesInput) (*models.Word, error) {
+	var wordInfo models.Word
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND lang = ? AND word = ?", userID, lang, word).First(&wordInfo).Error; err != nil {
+			return err
+		}
+
+		updates := map[string]any{}
+		if notes.Notes != nil {
+			updates["notes"] = *notes.Notes
+		}
+		if notes.Mnemonic != nil {
+			updates["mnemonic"] = *notes.Mnemonic
+		}
+		if notes.CustomDefinition != nil {
+			updates["custom_definition"] = *notes.CustomDefinition
+		}
+		if len(updates) == 0 {
+			return nil
+		}
+		return tx.Model(&wordInfo).Updates(updates).Error
+	})
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		log.Printf("Error updating notes for user %s, word %s: %v", userID, word, err)
+		return nil, err
+	}
+
+	return &wordInfo, nil
+}
+
+// likeEscaper escapes the wildcards of a LIKE pattern
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// WordsWithPrefix returns up to limit of the user's words starting with prefix, most searched first
+func (s *service) WordsWithPrefix(userID, lang, prefix string, limit int) ([]string, error) {
+	var words []string
+
+	err := s.db.Model(&models.Word{}).
+		Where("user_id = ? AND lang = ? AND word LIKE ?", userID, lang, likeEscaper.Replace(prefix)+"%").
+		Order("search_count DESC, word").
+		Limit(limit).
+		Pluck("word", &words).Error
+	if err != nil {
+		log.Printf("Error fetching words with prefix %s for user %s: %v", prefix, userID, err)
+		return nil, err
+	}
+
+	return words, nil
+}
+
+// WordReviewEvents returns the review log of a word, newest first
+func (s *service) WordReviewEvents(userID, lang, word string) ([]models.ReviewEvent, error) {
+	var events []models.ReviewEvent
+
+	err := s.db.Scopes(langScope(lang)).Where("user_id = ? AND word = ?", userID, word).Order("reviewed_at DESC").Find(&events).Error
+	if err != nil {
+		log.Printf("Error fetching review events for user %s, word %s: %v", userID, word, err)
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// WordSearchEvents returns the search log of a word, newest first
+func (s *service) WordSearchEvents(userID, lang, word string) ([]models.SearchEvent, error) {
+	var events []models.SearchEvent
+
+	err := s.db.Scopes(langScope(lang)).Where("user_id = ? AND word = ?", userID, word).Order("searched_at DESC").Find(&events).Error
+	if err != nil {
+		log.Printf("Error fetching search events for user %s, word %s: %v", userID, word, err)
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// AllWords returns every word of every user, including those in the trash, ordered by user, language pair and word
+func (s *service) AllWords() ([]models.Word, error) {
+	var words []models.Word
+
+	err := s.db.Unscoped().Order("user_id, lang, word").Find(&words).Error
+	if err != nil {
+		log.Printf("Error fetching words: %v", err)
+		return nil, err
+	}
+
+	return words, nil
+}
+
+// MergeWords merges the records of variants into word, e.g. "Running" and "ran" into "run".
+// Counters are added up, notes are joined, the schedule of the most recently reviewed record is kept
+// and the deck and tag links, review session cards and review and search logs are moved to word. 検索ログには元の綴りを SurfaceForm として残す
+// The merged word is in the trash or archived only if every record was, and is starred or
+// suspended if any record was; it stays buried until the latest date any record was buried until.
+func (s *service) MergeWords(userID, lang, word string, variants []string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var records []models.Word
+
+		words := append([]string{word}, variants...)
+		// 見出し語の記録を先頭に、残りは作成順に並べる
+		if err := tx.Unscoped().Where("user_id = ? AND lang = ? AND word IN ?", userID, lang, words).
+			Order(clause.OrderBy{Expression: clause.Expr{SQL: "word = ? DESC, created_at, word", Vars: []any{word}, WithoutParentheses: true}}).
+			Find(&records).Error; err != nil {
+			return err
+		}
+		if len(records) == 0 {
+			return nil
+		}
+
+		merged := records[0]
+		for _, record := range records[1:] {
+			merged.SearchCount += record.SearchCount
+			merged.ReviewCount += record.ReviewCount
+			merged.LapseCount += record.LapseCount
+			if !record.DeletedAt.Valid {
+				merged.DeletedAt = gorm.DeletedAt{}
+			}
+			if record.ArchivedAt == nil {
+				merged.ArchivedAt = nil
+			}
+			// どれか1つでもスター付き・保留中なら引き継ぎ、延期は最も遅い日時にする
+			merged.Starred = merged.Starred || record.Starred
+			merged.Suspended = merged.Suspended || record.Suspended
+			if record.BuriedUntil != nil && (merged.BuriedUntil == nil || record.BuriedUntil.After(*merged.BuriedUntil)) {
+				merged.BuriedUntil = record.BuriedUntil
+			}
+			if record.CreatedAt.Before(merged.CreatedAt) {
+				merged.CreatedAt = record.CreatedAt
+			}
+			// メモは失わないよう連結し、記憶術と自分の定義は見出し語の記録を優先する
+			if record.Notes != "" && record.Notes != merged.Notes {
+				if merged.Notes != "" {
+					merged.Notes += "\n\n"
+				}
+				merged.Notes += record.Notes
+			}
+			if merged.Mnemonic == "" {
+				merged.Mnemonic = record.Mnemonic
+			}
+			if merged.CustomDefinition == "" {
+				merged.CustomDefinition = record.CustomDefinition
+			}
+			// 最後に復習した記録のスケジュールを引き継ぐ（未復習同士なら期限の早い方）
+			if record.LastReviewed.After(merged.LastReviewed) ||
+				(record.LastReviewed.Equal(merged.LastReviewed) && record.DueAt.Before(merged.DueAt)) {
+				merged.LastReviewed = record.LastReviewed
+				merged.LastResponseTimeMs = record.LastResponseTimeMs
+				merged.EaseFactor = record.EaseFactor
+				merged.IntervalDays = record.IntervalDays
+				merged.Repetitions = record.Repetitions
+				merged.DueAt = record.DueAt
+				merged.Stability = record.Stability
+				merged.Difficulty = record.Difficulty
+			}
+		}
+		merged.Word = word
+		classify(&merged)
+
+		if err := tx.Unscoped().Where("user_id = ? AND lang = ? AND word IN ?", userID, lang, words).Delete(&models.Word{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&merged).Error; err != nil {
+			return err
+		}
+
+		// デッキとタグは見出し語に付け替える。見出し語がすでに入っているものは重複させない
+		for _, col := range []Collection{Decks, Tags} {
+			if err := tx.Exec(fmt.Sprintf("INSERT INTO %[1]s (%[2]s, user_id, word, lang, created_at) "+
+				"SELECT %[2]s, user_id, ?, lang, MIN(created_at) FROM %[1]s WHERE user_id = ? AND lang = ? AND word IN ? "+
+				"GROUP BY %[2]s, user_id, lang ON CONFLICT DO NOTHING", col.links, col.key),
+				word, userID, lang, variants).Error; err != nil {
+				return err
+			}
+			if err := tx.Table(col.links).Where("user_id = ? AND lang = ? AND word IN ?", userID, lang, variants).Delete(map[string]any{}).Error; err != nil {
+				return err
+			}
+		}
+
+		// 復習セッションのカードも見出し語に付け替える。同じセッションに見出し語のカードがあればそちらを残す
+		sessions := tx.Model(&models.ReviewSession{}).Select("id").Where("user_id = ?", userID)
+		if err := tx.Exec("INSERT INTO review_session_cards (session_id, word, lang, position, first_review, grade, response_time_ms, answered_at) "+
+			"SELECT session_id, ?, lang, position, first_review, grade, response_time_ms, answered_at FROM review_session_cards "+
+			"WHERE session_id IN (?) AND lang = ? AND word IN ? ORDER BY session_id, position ON CONFLICT DO NOTHING",
+			word, sessions, lang, variants).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("session_id IN (?) AND lang = ? AND word IN ?", sessions, lang, variants).Delete(&models.ReviewSessionCard{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.SearchEvent{}).
+			Where("user_id = ? AND lang = ? AND word IN ? AND surface_form = ''", userID, lang, variants).
+			Update("surface_form", gorm.Expr("word")).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.SearchEvent{}).
+			Where("user_id = ? AND lang = ? AND word IN ?", userID, lang, variants).
+			Update("word", word).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.ReviewEvent{}).
+			Where("user_id = ? AND lang = ? AND word IN ?", userID, lang, variants).
+			Update("word", word).Error
+	})
+}
+
+// SavedWords reports which of words are in the user's word list for lang
+func (s *service) SavedWords(userID, lang string, words []string) (map[string]bool, error) {
+	saved := map[string]bool{}
+	if len(words) == 0 {
+		return saved, nil
+	}
+
+	var found []string
+	err := s.db.Model(&models.Word{}).Where("user_id = ? AND lang = ? AND word IN ?", userID, lang, words).Pluck("word", &found).Error
+	if err != nil {
+		log.Printf("Error fetching saved words for user %s: %v", userID, err)
+		return nil, err
+	}
+
+	for _, word := range found {
+		saved[word] = true
+	}
+	return saved, nil
+}
+
+// DeleteWord moves a word to the trash. It reports whether the user had the word
+func (s *service) DeleteWord(userID, lang, word string) (bool, error) {
+	result := s.db.Where("user_id = ? AND lang = ? AND word = ?", userID, lang, word).Delete(&models.Word{})
+	if result.Error != nil {
+		log.Printf("Error deleting word %s for user %s: %v", word, userID, result.Error)
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// RestoreWord takes a word out of the trash and returns it, or nil if the word is not in the trash
+func (s *service) RestoreWord(userID, lang, word string) (*models.Word, error) {
+	var wordInfo models.Word
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().Where("user_id = ? AND lang = ? AND word = ? AND deleted_at IS NOT NULL", userID, lang, word).First(&wordInfo).Error
+		if err != nil {
+			return err
+		}
+		wordInfo.DeletedAt = gorm.DeletedAt{}
+		return tx.Unscoped().Model(&wordInfo).Update("deleted_at", nil).Error
+	})
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		log.Printf("Error restoring word %s for user %s: %v", word, userID, err)
+		return nil, err
+	}
+
+	return &wordInfo, nil
+}
+
+// TrashedWords returns the words in the user's trash, most recently deleted first
+func (s *service) TrashedWords(userID, lang string) ([]models.Word, error) {
+	var words []models.Word
+
+	err := s.db.Unscoped().Scopes(langScope(lang)).Where("user_id = ? AND deleted_at IS NOT NULL", userID).Order("deleted_at DESC").Find(&words).Error
+	if err != nil {
+		log.Printf("Error fetching trashed words for user %s: %v", userID, err)
+		return nil, err
+	}
+
+	return words, nil
+}
+
+// PurgeTrash permanently deletes the words of every user that were moved to the trash before
+// before, together with their deck and tag links, review session cards and review and search logs.
+// It returns the number of words deleted
+func (s *service) PurgeTrash(before time.Time) (int64, error) {
+	var purged int64
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		trashed := tx.Unscoped().Model(&models.Word{}).Select("user_id, word, lang").Where("deleted_at < ?", before)
+		for _, model := range []any{&models.DeckWord{}, &models.WordTag{}, &models.ReviewEvent{}, &models.SearchEvent{}} {
+			if err := tx.Where("(user_id, word, lang) IN (?)", trashed).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		// セッションのカードにはユーザーIDがないため、セッションからたどる
+		if err := tx.Where("EXISTS (SELECT 1 FROM review_sessions AS s WHERE s.id = review_session_cards.session_id "+
+			"AND (s.user_id, review_session_cards.word, review_session_cards.lang) IN (?))", trashed).
+			Delete(&models.ReviewSessionCard{}).Error; err != nil {
+			return err
+		}
+		result := tx.Unscoped().Where("deleted_at < ?", before).Delete(&models.Word{})
+		purged = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		log.Printf("Error purging trash: %v", err)
+		return 0, err
+	}
+
+	return purged, nil
+}
+
+// SetWordArchived archives a word, taking it out of the review queue, or brings it back.
+// It returns the updated word, or nil if the user has not saved the word
+func (s *service) SetWordArchived(userID, lang, word string, archived bool, now time.Time) (*models.Word, error) {
+	var wordInfo models.Word
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND lang = ? AND word = ?", userID, lang, word).First(&wordInfo).Error; err != nil {
+			return err
+		}
+		// アーカイブ済みの単語をもう一度アーカイブしても日時は変えない
+		if archived == (wordInfo.ArchivedAt != nil) {
+			return nil
+		}
+		var archivedAt *time.Time
+		if archived {
+			archivedAt = &now
+		}
+		return tx.Model(&wordInfo).Update("archived_at", archivedAt).Error
+	})
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		log.Printf("Error archiving word %s for user %s: %v", word, userID, err)
+		return nil, err
+	}
+
+	return &wordInfo, nil
+}
+
+// ArchivedWords returns the user's archived words, most recently archived first
+func (s *service) ArchivedWords(userID, lang string) ([]models.Word, error) {
+	var words []models.Word
+
+	err := s.db.Scopes(langScope(lang)).Where("user_id = ? AND archived_at IS NOT NULL", userID).Order("archived_at DESC").Find(&words).Error
+	if err != nil {
+		log.Printf("Error fetching archived words for user %s: %v", userID, err)
+		return nil, err
+	}
+
+	return words, nil
+}
+
+// SetWordFlag sets a review queue flag of a word to value and returns the updated word,
+// or nil if the user has not saved the word
+func (s *service) SetWordFlag(userID, lang, word string, flag WordFlag, value any) (*models.Word, error) {
+	var wordInfo models.Word
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND lang = ? AND word = ?", userID, lang, word).First(&wordInfo).Error; err != nil {
+			return err
+		}
+		return tx.Model(&wordInfo).Update(string(flag), value).Error
+	})
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		log.Printf("Error setting %s of word %s for user %s: %v", flag, word, userID, err)
+		return nil, err
+	}
+
+	return &wordInfo, nil
+}
+
+// SuspendedWords returns the user's suspended words, ordered by word
+func (s *service) SuspendedWords(userID, lang string) ([]models.Word, error) {
+	var words []models.Word
+
+	err := s.db.Scopes(langScope(lang)).Where("user_id = ? AND suspended", userID).Order("word, lang").Find(&words).Error
+	if err != nil {
+		log.Printf("Error fetching suspended words for user %s: %v", userID, err)
+		return nil, err
+	}
+
+	return words, nil
+}
+
+// CreateReviewSession starts a review session with up to size words of the review queue at now,
+// in queue order, and returns it. It returns nil if no word is due
+func (s *service) CreateReviewSession(userID, lang string, now time.Time, opts PendingOptions, size int) (*ReviewSessionInfo, error) {
+	var words []models.Word
+	if err := s.pendingQuery(userID, lang, now, opts).Limit(size).Find(&words).Error; err != nil {
+		log.Printf("Error fetching pending words for user %s: %v", userID, err)
+		return nil, err
+	}
+	if len(words) == 0 {
+		return nil, nil
+	}
+
+	session := models.ReviewSession{
+		UserID:    userID,
+		Lang:      lang,
+		StartedAt: now,
+	}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&session).Error; err != nil {
+			return err
+		}
+		cards := make([]models.ReviewSessionCard, len(words))
+		for i, w := range words {
+			cards[i] = models.ReviewSessionCard{
+				SessionID:   session.ID,
+				Word:        w.Word,
+				Lang:        w.Lang,
+				Position:    i,
+				FirstReview: w.ReviewCount == 0,
+			}
+		}
+		return tx.Create(&cards).Error
+	})
+	if err != nil {
+		log.Printf("Error creating review session for user %s: %v", userID, err)
+		return nil, err
+	}
+
+	return s.sessionInfo(session)
+}
+
+// GetReviewSession returns a review session of the user, or nil if there is none
+func (s *service) GetReviewSession(userID string, id uint) (*ReviewSessionInfo, error) {
+	var session models.ReviewSession
+
+	err := s.db.Where("id = ? AND user_id = ?", id, userID).First(&session).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		log.Printf("Error fetching review session %d for user %s: %v", id, userID, err)
+		return nil, err
+	}
+
+	return s.sessionInfo(session)
+}
+
+// CurrentReviewSession returns the most recently started unfinished review session of the user,
+// or nil if every session is finished
+func (s *service) CurrentReviewSession(userID string) (*ReviewSessionInfo, error) {
+	var session models.ReviewSession
+
+	err := s.db.Where("user_id = ? AND finished_at IS NULL", userID).Order("started_at DESC, id DESC").First(&session).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		log.Printf("Error fetching current review session for user %s: %v", userID, err)
+		return nil, err
+	}
+
+	return s.sessionInfo(session)
+}
+
+// sessionInfo loads the cards of a review session with the current content of their words
+func (s *service) sessionInfo(session models.ReviewSession) (*ReviewSessionInfo, error) {
+	info := &ReviewSessionInfo{ReviewSession: session}
+
+	err := s.db.Table("review_session_cards AS c").
+		Select("c.*, w.kind, w.level, w.notes, w.mnemonic, w.custom_definition").
+		Joins("LEFT JOIN words AS w ON w.user_id = ? AND w.word = c.word AND w.lang = c.lang", session.UserID).
+		Where("c.session_id = ?", session.ID).
+		Order("c.position").
+		Scan(&info.Cards).Error
+	if err != nil {
+		log.Printf("Error fetching cards of review session %d: %v", session.ID, err)
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// AnswerReviewSession records the answer to a card of a review session and reschedules its word
+// as UpdateWordReview does, in the same transaction. Each card can be answered once
+func (s *service) AnswerReviewSession(userID string, id uint, lang, word string, sched scheduler.Scheduler, review ReviewInput) error {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var session models.ReviewSession
+		if err := tx.Where("id = ? AND user_id = ?", id, userID).First(&session).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return ErrSessionNotFound
+			}
+			return err
+		}
+		if session.FinishedAt != nil {
+			return ErrSessionFinished
+		}
+
+		// 同じカードへの回答が同時に届いても一度だけ記録する
+		var card models.ReviewSessionCard
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("session_id = ? AND lang = ? AND word = ?", id, lang, word).First(&card).Error
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return ErrCardNotFound
+			}
+			return err
+		}
+		if card.AnsweredAt != nil {
+			return ErrCardAnswered
+		}
+
+		if err := reviewWord(tx, userID, lang, word, sched, review); err != nil {
+			return err
+		}
+		return tx.Model(&card).Updates(map[string]any{
+			"grade":            review.Grade.String(),
+			"response_time_ms": review.ResponseTimeMs,
+			"answered_at":      review.ReviewedAt,
+		}).Error
+	})
+	if err != nil && !errors.Is(err, ErrSessionNotFound) && !errors.Is(err, ErrSessionFinished) &&
+		!errors.Is(err, ErrCardNotFound) && !errors.Is(err, ErrCardAnswered) {
+		log.Printf("Error answering review session %d for user %s, word %s: %v", id, userID, word, err)
+	}
+	return err
+}
+
+// FinishReviewSession marks a review session as finished at now and returns it, or nil if the
+// user has no such session. Finishing a session again keeps the first finish time
+func (s *service) FinishReviewSession(userID string, id uint, now time.Time) (*ReviewSessionInfo, error) {
+	var session models.ReviewSession
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ? AND user_id = ?", id, userID).First(&session).Error; err != nil {
+			return err
+		}
+		if session.FinishedAt != nil {
+			return nil
+		}
+		return tx.Model(&session).Update("finished_at", now).Error
+	})
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		log.Printf("Error finishing review session %d for user %s: %v", id, userID, err)
+		return nil, err
+	}
+
+	return s.sessionInfo(session)
+}
+
+// collectionRow holds the columns shared by models.Deck and models.Tag
+type collectionRow struct {
+	ID          uint `gorm:"primaryKey"`
+	UserID      string
+	Name        string
+	Description string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// collectionQuery selects the decks or tags of a user with the number of words in each.
+// ゴミ箱にある単語は数えない
+func (s *service) collectionQuery(col Collection, userID string) *gorm.DB {
+	return s.db.Table(col.table+" AS c").
+		Select("c.id, c.name, c.description, c.created_at, c.updated_at, COUNT(w.word) AS word_count").
+		Joins(fmt.Sprintf("LEFT JOIN %s AS l ON l.%s = c.id", col.links, col.key)).
+		Joins("LEFT JOIN words AS w ON w.user_id = l.user_id AND w.word = l.word AND w.lang = l.lang AND w.deleted_at IS NULL").
+		Where("c.user_id = ?", userID).
+		Group("c.id")
+}
+
+// ListCollections returns the decks or tags of a user ordered by name
+func (s *service) ListCollections(col Collection, userID string) ([]CollectionInfo, error) {
+	var infos []CollectionInfo
+
+	err := s.collectionQuery(col, userID).Order("c.name").Scan(&infos).Error
+	if err != nil {
+		log.Printf("Error fetching %s for user %s: %v", col.table, userID, err)
+		return nil, err
+	}
+
+	return infos, nil
+}
+
+// GetCollection returns a deck or tag of the user, or nil if there is none
+func (s *service) GetCollection(col Collection, userID string, id uint) (*CollectionInfo, error) {
+	var infos []CollectionInfo
+
+	err := s.collectionQuery(col, userID).Where("c.id = ?", id).Scan(&infos).Error
+	if err != nil {
+		log.Printf("Error fetching %s %d for user %s: %v", col.table, id, userID, err)
+		return nil, err
+	}
+	if len(infos) == 0 {
+		return nil, nil
+	}
+
+	return &infos[0], nil
+}
+
+// nameTaken reports whether the user has another deck or tag named name
+func nameTaken(tx *gorm.DB, col Collection, userID, name string, id uint) (bool, error) {
+	var count int64
+	err := tx.Table(col.table).Where("user_id = ? AND name = ? AND id <> ?", userID, name, id).Count(&count).Error
+	return count > 0, err
+}
+
+// CreateCollection creates a deck or tag. ErrNameTaken is returned if the name is in use
+func (s *service) CreateCollection(col Collection, userID string, input CollectionInput) (*CollectionInfo, error) {
+	row := collectionRow{
+		UserID:      userID,
+		Name:        input.Name,
+		Description: input.Description,
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		taken, err := nameTaken(tx, col, userID, input.Name, 0)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return ErrNameTaken
+		}
+		return tx.Table(col.table).Create(&row).Error
+	})
+	if err != nil {
+		if !errors.Is(err, ErrNameTaken) {
+			log.Printf("Error creating %s for user %s: %v", col.table, userID, err)
+		}
+		return nil, err
+	}
+
+	return &CollectionInfo{
+		ID:          row.ID,
+		Name:        row.Name,
+		Description: row.Description,
+		CreatedAt:   row.CreatedAt,
+		UpdatedAt:   row.UpdatedAt,
+	}, nil
+}
+
+// UpdateCollection renames a deck or tag and replaces its description. It returns nil if the
+// user has no such deck or tag, and ErrNameTaken if the new name is in use
+func (s *service) UpdateCollection(col Collection, userID string, id uint, input CollectionInput) (*CollectionInfo, error) {
+	var updated bool
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		taken, err := nameTaken(tx, col, userID, input.Name, id)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return ErrNameTaken
+		}
+		result := tx.Table(col.table).Where("id = ? AND user_id = ?", id, userID).Updates(map[string]any{
+			"name":        input.Name,
+			"description": input.Description,
+			"updated_at":  time.Now(),
+		})
+		updated = result.RowsAffected > 0
+		return result.Error
+	})
+	if err != nil {
+		if !errors.Is(err, ErrNameTaken) {
+			log.Printf("Error updating %s %d for user %s: %v", col.table, id, userID, err)
+		}
+		return nil, err
+	}
+	if !updated {
+		return nil, nil
+	}
+
+	return s.GetCollection(col, userID, id)
+}
+
+// DeleteCollection deletes a deck or tag and its links to words; the words themselves are kept.
+// It reports whether the user had such a deck or tag
+func (s *service) DeleteCollection(col Collection, userID string, id uint) (bool, error) {
+	var deleted bool
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table(col.links).Where(col.key+" = ? AND user_id = ?", id, userID).Delete(map[string]any{}).Error; err != nil {
+			return err
+		}
+		result := tx.Table(col.table).Where("id = ? AND user_id = ?", id, userID).Delete(map[string]any{})
+		deleted = result.RowsAffected > 0
+		return result.Error
+	})
+	if err != nil {
+		log.Printf("Error deleting %s %d for user %s: %v", col.table, id, userID, err)
+		return false, err
+	}
+
+	return deleted, nil
+}
+
+// CollectionWords returns the words in a deck or tag of the user, ordered by word
+func (s *service) CollectionWords(col Collection, userID string, id uint) ([]models.Word, error) {
+	var words []models.Word
+
+	err := s.db.Scopes(collectionScope(col, id)).Where("user_id = ?", userID).Order("word, lang").Find(&words).Error
+	if err != nil {
+		log.Printf("Error fetching words of %s %d for user %s: %v", col.table, id, userID, err)
+		return nil, err
+	}
+
+	return words, nil
+}
+
+// AddCollectionWord puts a word in a deck or tag; adding a word twice has no effect
+func (s *service) AddCollectionWord(col Collection, userID string, id uint, lang, word string) error {
+	return s.db.Table(col.links).Clauses(clause.OnConflict{DoNothing: true}).Create(map[string]any{
+		col.key:      id,
+		"user_id":    userID,
+		"word":       word,
+		"lang":       lang,
+		"created_at": time.Now(),
+	}).Error
+}
+
+// RemoveCollectionWord takes a word out of a deck or tag
+func (s *service) RemoveCollectionWord(col Collection, userID string, id uint, lang, word string) error {
+	return s.db.Table(col.links).
+		Where(col.key+" = ? AND user_id = ? AND word = ? AND lang = ?", id, userID, word, lang).
+		Delete(map[string]any{}).Error
+}
+
+// UserReviewEvents returns every review event of a user, grouped by language pair and word in chronological order
+func (s *service) UserReviewEvents(userID string) ([]models.ReviewEvent, error) {
+	var events []models.ReviewEvent
+
+	err := s.db.Where("user_id = ?", userID).Order("lang, word, reviewed_at").Find(&events).Error
+	if err != nil {
+		log.Printf("Error fetching review events for user %s: %v", userID, err)
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// ReviewedUserIDs returns the users who have logged at least minReviews reviews
+func (s *service) ReviewedUserIDs(minReviews int) ([]string, error) {
+	var userIDs []string
+
+	err := s.db.Model(&models.ReviewEvent{}).
+		Group("user_id").
+		Having("COUNT(*) >= ?", minReviews).
+		Pluck("user_id", &userIDs).Error
+	if err != nil {
+		log.Printf("Error fetching reviewed users: %v", err)
+		return nil, err
+	}
+
+	return userIDs, nil
+}
+
+// GetFSRSParameters returns the fitted FSRS parameters of a user, or nil if none have been fitted yet
+func (s *service) GetFSRSParameters(userID string) (*models.FSRSParameters, error) {
+	var params models.FSRSParameters
+
+	result := s.db.Where("user_id = ?", userID).First(&params)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+
+	return &params, nil
+}
+
+// SaveFSRSParameters creates or replaces the fitted FSRS parameters of a user
+func (s *service) SaveFSRSParameters(params *models.FSRSParameters) error {
+	return s.db.Save(params).Error
+}
+
+// LookupEntry returns the meaning of word in dir from the imported offline dictionary.
+// 複数のデータセットに見出し語がある場合は意味を連結する
+func (s *service) LookupEntry(ctx context.Context, word string, dir dictionary.Direction) (string, bool, error) {
+	var entries []models.LexiconEntry
+
+	// インポート時に見出し語を小文字にしている（日本語の見出し語はそのまま）
+	headword := word
+	if dir.Source() != "ja" {
+		headword = strings.ToLower(word)
+	}
+
+	err := s.db.WithContext(ctx).Where("headword = ? AND direction = ?", headword, string(dir)).Order("source").Find(&entries).Error
+	if err != nil {
+		log.Printf("Error looking up lexicon entry %s: %v", word, err)
+		return "", false, err
+	}
+	if len(entries) == 0 {
+		return "", false, nil
+	}
+
+	meanings := make([]string, len(entries))
+	for i, entry := range entries {
+		meanings[i] = entry.Meaning
+	}
+	return strings.Join(meanings, " / "), true, nil
+}
+
+// ReplaceLexiconEntries replaces every offline dictionary entry in dir imported from source
+func (s *service) ReplaceLexiconEntries(source string, dir dictionary.Direction, entries map[string]string) error {
+	rows := make([]models.LexiconEntry, 0, len(entries))
+	for headword, meaning := range entries {
+		rows = append(rows, models.LexiconEntry{
+			Headword:  headword,
+			Direction: string(dir),
+			Source:    source,
+			Meaning:   meaning,
+		})
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("source = ? AND direction = ?", source, string(dir)).Delete(&models.LexiconEntry{}).Error; err != nil {
+			return err
+		}
+		return tx.CreateInBatches(rows, 1000).Error
+	})
+}
+
+// LexiconHeadwords returns every headword of the offline dictionary, grouped by direction
+func (s *service) LexiconHeadwords() (map[dictionary.Direction][]string, error) {
+	var rows []struct {
+		Headword  string
+		Direction string
+	}
+
+	err := s.db.Model(&models.LexiconEntry{}).Distinct("headword", "direction").Find(&rows).Error
+	if err != nil {
+		log.Printf("Error fetching lexicon headwords: %v", err)
+		return nil, err
+	}
+
+	headwords := map[dictionary.Direction][]string{}
+	for _, row := range rows {
+		dir := dictionary.Direction(row.Direction)
+		headwords[dir] = append(headwords[dir], row.Headword)
+	}
+	return headwords, nil
+}
+
+// WordRelations returns the words related to an English headword, ordered by relation and related word
+func (s *service) WordRelations(word string) ([]models.WordRelation, error) {
+	var relations []models.WordRelation
+
+	err := s.db.Where("word = ?", word).Order("relation, related, source").Find(&relations).Error
+	if err != nil {
+		log.Printf("Error fetching word relations for %s: %v", word, err)
+		return nil, err
+	}
+
+	return relations, nil
+}
+
+// ReplaceWordRelations replaces every word relation imported from source
+func (s *service) ReplaceWordRelations(source string, links []wordnet.Link) error {
+	rows := make([]models.WordRelation, len(links))
+	for i, link := range links {
+		rows[i] = models.WordRelation{
+			Word:         link.Word,
+			Relation:     string(link.Relation),
+			Related:      link.Related,
+			Source:       source,
+			PartOfSpeech: link.PartOfSpeech,
+		}
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("source = ?", source).Delete(&models.WordRelation{}).Error; err != nil {
+			return err
+		}
+		return tx.CreateInBatches(rows, 1000).Error
+	})
+}
+
+// ExampleSentences returns up to limit of the example sentences in lang containing every token,
+// shortest first
+func (s *service) ExampleSentences(lang string, tokens []string, limit int) ([]models.ExampleSentence, error) {
+	var sentences []models.ExampleSentence
+	if len(tokens) == 0 {
+		return sentences, nil
+	}
+
+	matching := s.db.Model(&models.ExampleToken{}).
+		Select("sentence_id").
+		Where("token IN ?", tokens).
+		Group("sentence_id").
+		Having("COUNT(DISTINCT token) = ?", len(tokens))
+	err := s.db.Where("lang = ? AND id IN (?)", lang, matching).
+		Order("length, id").
+		Limit(limit).
+		Find(&sentences).Error
+	if err != nil {
+		log.Printf("Error fetching example sentences for %v: %v", tokens, err)
+		return nil, err
+	}
+
+	return sentences, nil
+}
+
+// ReplaceExampleSentences replaces every example sentence in lang imported from source
+// and indexes the headwords of the new sentences
+func (s *service) ReplaceExampleSentences(source string, lang dictionary.Direction, pairs []corpus.Pair) error {
+	sentences := make([]models.ExampleSentence, len(pairs))
+	tokens := make([][]string, len(pairs))
+	for i, pair := range pairs {
+		tokens[i] = corpus.Tokens(pair.Text, lang.Source())
+		sentences[i] = models.ExampleSentence{
+			Lang:        string(lang),
+			Source:      source,
+			Text:        pair.Text,
+			Translation: pair.Translation,
+			Length:      len(strings.Fields(pair.Text)),
+		}
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		previous := tx.Model(&models.ExampleSentence{}).Select("id").Where("source = ? AND lang = ?", source, string(lang))
+		if err := tx.Where("sentence_id IN (?)", previous).Delete(&models.ExampleToken{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("source = ? AND lang = ?", source, string(lang)).Delete(&models.ExampleSentence{}).Error; err != nil {
+			return err
+		}
+		// 作成時に採番された ID で索引を作る
+		if err := tx.CreateInBatches(sentences, 1000).Error; err != nil {
+			return err
+		}
+
+		var rows []models.ExampleToken
+		for i, sentence := range sentences {
+			for _, token := range tokens[i] {
+				rows = append(rows, models.ExampleToken{Token: token, SentenceID: sentence.ID})
+			}
+		}
+		return tx.CreateInBatches(rows, 1000).Error
+	})
+}
+
+// GetDictionaryEntry returns the cached dictionary lookup result for key, or nil if there is none
+func (s *service) GetDictionaryEntry(ctx context.Context, key string) (*models.DictionaryEntry, error) {
+	var entry models.DictionaryEntry
+
+	result := s.db.WithContext(ctx).Where("key = ?", key).First(&entry)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+
+	return &entry, nil
+}
+
+// SaveDictionaryEntry creates or replaces a cached dictionary lookup result
+func (s *service) SaveDictionaryEntry(ctx context.Context, entry *models.DictionaryEntry) error {
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"entry", "not_found", "expires_at", "updated_at"}),
+	}).Create(entry).Error
+}
@@ -0,0 +1,124 @@
+package dictionary
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned while the circuit breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// HealthReporter is implemented by providers that can report their state to /health.
+type HealthReporter interface {
+	Health() map[string]string
+}
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+func (s breakerState) String() string {
+	switch s {
+	case breakerOpen:
+		return "open"
+	case breakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// Breaker is a circuit breaker around a provider. After FailureThreshold
+// consecutive failures it fails fast for OpenTimeout, then lets a single
+// lookup through to probe whether the provider has recovered.
+// ErrNotFound is a normal answer and does not count as a failure.
+type Breaker struct {
+	provider         Provider
+	failureThreshold int
+	openTimeout      time.Duration
+
+	mu       sync.Mutex
+	state    breakerState
+	failures int
+	openedAt time.Time
+}
+
+func NewBreaker(provider Provider, failureThreshold int, openTimeout time.Duration) *Breaker {
+	return &Breaker{
+		provider:         provider,
+		failureThreshold: failureThreshold,
+		openTimeout:      openTimeout,
+	}
+}
+
+func (b *Breaker) Name() string {
+	return b.provider.Name()
+}
+
+func (b *Breaker) Supports(dir Direction) bool {
+	return b.provider.Supports(dir)
+}
+
+func (b *Breaker) Lookup(ctx context.Context, q Query) (*Entry, error) {
+	if !b.allow() {
+		return nil, ErrCircuitOpen
+	}
+
+	entry, err := b.provider.Lookup(ctx, q)
+	b.record(err == nil || errors.Is(err, ErrNotFound))
+	return entry, err
+}
+
+// allow reports whether a lookup may be sent to the provider.
+func (b *Breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if time.Since(b.openedAt) < b.openTimeout {
+			return false
+		}
+		// 一定時間経過したら1件だけ試す
+		b.state = breakerHalfOpen
+		return true
+	case breakerHalfOpen:
+		return false
+	default:
+		return true
+	}
+}
+
+func (b *Breaker) record(success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if success {
+		b.state = breakerClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.failureThreshold {
+		b.state = breakerOpen
+		b.openedAt = time.Now()
+	}
+}
+
+func (b *Breaker) Health() map[string]string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return map[string]string{
+		"breaker":          b.state.String(),
+		"breaker_failures": strconv.Itoa(b.failures),
+	}
+}
@@ -0,0 +1,147 @@
+package dictionary
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// stubProvider returns err from every lookup and counts the lookups it receives.
+type stubProvider struct {
+	err   error
+	calls int
+}
+
+func (p *stubProvider) Name() string {
+	return "stub"
+}
+
+func (p *stubProvider) Supports(Direction) bool {
+	return true
+}
+
+func (p *stubProvider) Lookup(_ context.Context, q Query) (*Entry, error) {
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &Entry{Word: q.Word, Headword: q.Word}, nil
+}
+
+func TestBreaker(t *testing.T) {
+	errUnavailable := errors.New("service unavailable")
+
+	type step struct {
+		// err is what the provider returns
+		err error
+		// elapsed moves the breaker past its open timeout before the lookup
+		elapsed   bool
+		wantErr   error
+		wantState breakerState
+	}
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "not found is not a failure",
+			steps: []step{
+				{err: ErrNotFound, wantErr: ErrNotFound, wantState: breakerClosed},
+				{err: ErrNotFound, wantErr: ErrNotFound, wantState: breakerClosed},
+				{err: ErrNotFound, wantErr: ErrNotFound, wantState: breakerClosed},
+				{err: ErrNotFound, wantErr: ErrNotFound, wantState: breakerClosed},
+			},
+		},
+		{
+			name: "opens after consecutive failures",
+			steps: []step{
+				{err: errUnavailable, wantErr: errUnavailable, wantState: breakerClosed},
+				{err: errUnavailable, wantErr: errUnavailable, wantState: breakerClosed},
+				{err: errUnavailable, wantErr: errUnavailable, wantState: breakerOpen},
+				{wantErr: ErrCircuitOpen, wantState: breakerOpen},
+			},
+		},
+		{
+			name: "a success resets the failure count",
+			steps: []step{
+				{err: errUnavailable, wantErr: errUnavailable, wantState: breakerClosed},
+				{err: errUnavailable, wantErr: errUnavailable, wantState: breakerClosed},
+				{wantState: breakerClosed},
+				{err: errUnavailable, wantErr: errUnavailable, wantState: breakerClosed},
+				{err: errUnavailable, wantErr: errUnavailable, wantState: breakerClosed},
+			},
+		},
+		{
+			name: "a successful probe closes the breaker",
+			steps: []step{
+				{err: errUnavailable, wantErr: errUnavailable, wantState: breakerClosed},
+				{err: errUnavailable, wantErr: errUnavailable, wantState: breakerClosed},
+				{err: errUnavailable, wantErr: errUnavailable, wantState: breakerOpen},
+				{elapsed: true, wantState: breakerClosed},
+				{err: errUnavailable, wantErr: errUnavailable, wantState: breakerClosed},
+			},
+		},
+		{
+			name: "a failed probe opens the breaker again",
+			steps: []step{
+				{err: errUnavailable, wantErr: errUnavailable, wantState: breakerClosed},
+				{err: errUnavailable, wantErr: errUnavailable, wantState: breakerClosed},
+				{err: errUnavailable, wantErr: errUnavailable, wantState: breakerOpen},
+				{err: errUnavailable, elapsed: true, wantErr: errUnavailable, wantState: breakerOpen},
+				{wantErr: ErrCircuitOpen, wantState: breakerOpen},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &stubProvider{}
+			breaker := NewBreaker(provider, 3, time.Minute)
+
+			for i, step := range tt.steps {
+				if step.elapsed {
+					breaker.openedAt = breaker.openedAt.Add(-time.Minute)
+				}
+				provider.err = step.err
+				calls := provider.calls
+
+				_, err := breaker.Lookup(context.Background(), Query{Word: "word", Direction: EnJa})
+				if !errors.Is(err, step.wantErr) || (err == nil) != (step.wantErr == nil) {
+					t.Errorf("step %d: err = %v, want %v", i, err, step.wantErr)
+				}
+				if breaker.state != step.wantState {
+					t.Errorf("step %d: state = %s, want %s", i, breaker.state, step.wantState)
+				}
+				// 開いている間はプロバイダを呼ばない
+				if called := provider.calls > calls; called == errors.Is(step.wantErr, ErrCircuitOpen) {
+					t.Errorf("step %d: provider called = %v", i, called)
+				}
+			}
+		})
+	}
+}
+
+func TestBreakerHalfOpenAllowsOneProbe(t *testing.T) {
+	breaker := NewBreaker(&stubProvider{}, 1, time.Minute)
+	breaker.record(false)
+	breaker.openedAt = breaker.openedAt.Add(-time.Minute)
+
+	tests := []struct {
+		wantAllow bool
+		wantState breakerState
+	}{
+		{true, breakerHalfOpen},
+		{false, breakerHalfOpen},
+		{false, breakerHalfOpen},
+	}
+	for i, tt := range tests {
+		if allow := breaker.allow(); allow != tt.wantAllow {
+			t.Errorf("call %d: allow = %v, want %v", i, allow, tt.wantAllow)
+		}
+		if breaker.state != tt.wantState {
+			t.Errorf("call %d: state = %s, want %s", i, breaker.state, tt.wantState)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package dictionary
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"strconv"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"tsumitan/internal/models"
+)
+
+// EntryStore persists cached lookup results across restarts.
+type EntryStore interface {
+	// GetDictionaryEntry returns the cached entry for key, or nil if there is none.
+	GetDictionaryEntry(ctx context.Context, key string) (*models.DictionaryEntry, error)
+	SaveDictionaryEntry(ctx context.Context, entry *models.DictionaryEntry) error
+}
+
+// CacheConfig controls how long lookup results are cached.
+type CacheConfig struct {
+	// Size is the maximum number of items kept in memory
+	Size int
+	// TTL is how long found entries are kept in memory
+	TTL time.Duration
+	// StoreTTL is how long found entries are kept in the persistent store
+	StoreTTL time.Duration
+	// NegativeTTL is how long words that were not found are cached in both tiers
+	NegativeTTL time.Duration
+	// LookupTimeout bounds a lookup shared by concurrent misses, which outlives the caller that started it
+	LookupTimeout time.Duration
+}
+
+var DefaultCacheConfig = CacheConfig{
+	Size:          10000,
+	TTL:           24 * time.Hour,
+	StoreTTL:      30 * 24 * time.Hour,
+	NegativeTTL:   time.Hour,
+	LookupTimeout: 30 * time.Second,
+}
+
+// CachedProvider caches the results of the wrapped provider in an in-memory
+// LRU tier and, below it, a persistent store. Concurrent misses for the same
+// word share a single lookup.
+type CachedProvider struct {
+	provider Provider
+	lru      *LRU
+	store    EntryStore
+	config   CacheConfig
+	group    singleflight.Group
+}
+
+// NewCachedProvider wraps provider with a cache. store may be nil to cache in memory only.
+func NewCachedProvider(provider Provider, store EntryStore, config CacheConfig) *CachedProvider {
+	return &CachedProvider{
+		provider: provider,
+		lru:      NewLRU(config.Size),
+		store:    store,
+		config:   config,
+	}
+}
+
+func (p *CachedProvider) Name() string {
+	return p.provider.Name()
+}
+
+func (p *CachedProvider) Supports(dir Direction) bool {
+	return p.provider.Supports(dir)
+}
+
+func (p *CachedProvider) Lookup(ctx context.Context, q Query) (*Entry, error) {
+	now := time.Now()
+	key := q.key()
+
+	if item, found := p.lru.Get(key, now); found {
+		return item.result()
+	}
+
+	// 同じ単語への同時リクエストは1回の検索にまとめる。
+	// 最初の呼び出し元がキャンセルしても他の呼び出し元を巻き込まないよう、共有する検索は
+	// 呼び出し元のコンテキストから切り離して独自のタイムアウトで実行する
+	results := p.group.DoChan(key, func() (any, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), p.config.LookupTimeout)
+		defer cancel()
+		return p.lookupMiss(ctx, q, now)
+	})
+
+	select {
+	case result := <-results:
+		if result.Err != nil {
+			return nil, result.Err
+		}
+		return result.Val.(*Entry), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// lookupMiss loads q from the persistent store or the provider and caches the result.
+func (p *CachedProvider) lookupMiss(ctx context.Context, q Query, now time.Time) (*Entry, error) {
+	key := q.key()
+
+	if item, found := p.loadFromStore(ctx, key, now); found {
+		p.lru.Add(item)
+		return item.result()
+	}
+
+	entry, err := p.provider.Lookup(ctx, q)
+	switch {
+	case err == nil:
+		p.lru.Add(cacheItem{key: key, entry: entry, expiresAt: now.Add(p.config.TTL)})
+		p.saveToStore(ctx, key, entry, now.Add(p.config.StoreTTL))
+		return entry, nil
+	case errors.Is(err, ErrNotFound):
+		// 見つからなかった単語も一定時間キャッシュする
+		expiresAt := now.Add(p.config.NegativeTTL)
+		p.lru.Add(cacheItem{key: key, expiresAt: expiresAt})
+		p.saveToStore(ctx, key, nil, expiresAt)
+		return nil, err
+	default:
+		return nil, err
+	}
+}
+
+func (item cacheItem) result() (*Entry, error) {
+	if item.entry == nil {
+		return nil, ErrNotFound
+	}
+	return item.entry, nil
+}
+
+// loadFromStore returns the persisted item for key. Store errors are logged and treated as a miss.
+func (p *CachedProvider) loadFromStore(ctx context.Context, key string, now time.Time) (cacheItem, bool) {
+	if p.store == nil {
+		return cacheItem{}, false
+	}
+
+	stored, err := p.store.GetDictionaryEntry(ctx, key)
+	if err != nil {
+		log.Printf("dictionary cache read failed for %q: %v", key, err)
+		return cacheItem{}, false
+	}
+	if stored == nil || !now.Before(stored.ExpiresAt) {
+		return cacheItem{}, false
+	}
+
+	// メモリ上のTTLは永続キャッシュの期限を超えないようにする
+	expiresAt := now.Add(p.config.TTL)
+	if stored.ExpiresAt.Before(expiresAt) {
+		expiresAt = stored.ExpiresAt
+	}
+
+	item := cacheItem{key: key, expiresAt: expiresAt}
+	if !stored.NotFound {
+		var entry Entry
+		if err := json.Unmarshal([]byte(stored.Entry), &entry); err != nil {
+			log.Printf("dictionary cache entry for %q is corrupt: %v", key, err)
+			return cacheItem{}, false
+		}
+		item.entry = &entry
+	}
+	return item, true
+}
+
+// saveToStore persists a lookup result. Failures only cost a future cache miss, so they are logged.
+func (p *CachedProvider) saveToStore(ctx context.Context, key string, entry *Entry, expiresAt time.Time) {
+	if p.store == nil {
+		return
+	}
+
+	stored := &models.DictionaryEntry{
+		Key:       key,
+		NotFound:  entry == nil,
+		ExpiresAt: expiresAt,
+	}
+	if entry != nil {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			log.Printf("failed to encode dictionary entry for %q: %v", key, err)
+			return
+		}
+		stored.Entry = string(data)
+	}
+
+	if err := p.store.SaveDictionaryEntry(ctx, stored); err != nil {
+		log.Printf("dictionary cache write failed for %q: %v", key, err)
+	}
+}
+
+// Health reports the cache size and the state of the wrapped provider.
+func (p *CachedProvider) Health() map[string]string {
+	stats := map[string]string{
+		"cache_size": strconv.Itoa(p.lru.Len()),
+	}
+	if reporter, ok := p.provider.(HealthReporter); ok {
+		for key, value := range reporter.Health() {
+			stats[key] = value
+		}
+	}
+	return stats
+}
@@ -0,0 +1,64 @@
+package dictionary
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+)
+
+// Chain tries each provider in order and returns the first meaning found.
+type Chain []Provider
+
+func (c Chain) Name() string {
+	names := make([]string, len(c))
+	for i, p := range c {
+		names[i] = p.Name()
+	}
+	return strings.Join(names, ",")
+}
+
+func (c Chain) Supports(dir Direction) bool {
+	for _, p := range c {
+		if p.Supports(dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// Lookup skips providers that do not support the query direction. It returns
+// ErrNotFound only if every remaining provider reported the word as not found,
+// otherwise the last provider error is returned.
+func (c Chain) Lookup(ctx context.Context, q Query) (*Entry, error) {
+	err := ErrNotFound
+	for _, p := range c {
+		if !p.Supports(q.Direction) {
+			continue
+		}
+		entry, lookupErr := p.Lookup(ctx, q)
+		if lookupErr == nil {
+			return entry, nil
+		}
+		if !errors.Is(lookupErr, ErrNotFound) {
+			log.Printf("dictionary provider %s failed for %q: %v", p.Name(), q.Word, lookupErr)
+			err = lookupErr
+		}
+	}
+	return nil, err
+}
+
+// Health reports the state of every provider in the chain that implements HealthReporter.
+func (c Chain) Health() map[string]string {
+	stats := map[string]string{}
+	for _, p := range c {
+		reporter, ok := p.(HealthReporter)
+		if !ok {
+			continue
+		}
+		for key, value := range reporter.Health() {
+			stats[p.Name()+"_"+key] = value
+		}
+	}
+	return stats
+}
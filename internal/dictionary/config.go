@@ -0,0 +1,165 @@
+package dictionary
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FromEnv builds the provider chain configured by the environment.
+// The offline provider reads from lexicon unless DICTIONARY_OFFLINE_FILE is set,
+// lookup results are cached in memory and in cacheStore, and phrases without an
+// entry of their own are looked up through their head word.
+//
+//	DICTIONARY_PROVIDERS          comma separated provider names in fallback order (default "http")
+//	DICTIONARY_HTTP_URL           base URL of the HTTP provider for English-Japanese lookups
+//	DICTIONARY_HTTP_JAEN_URL      base URL of the HTTP provider for Japanese-English lookups (optional)
+//	DICTIONARY_HTTP_URLS          base URLs for other directions, e.g. "de-ja=https://...,ja-de=https://..."
+//	DICTIONARY_HTTP_TIMEOUT       timeout of a single HTTP request (default "5s")
+//	DICTIONARY_HTTP_RETRIES       extra attempts after a failed HTTP request (default 2)
+//	DICTIONARY_BREAKER_FAILURES   consecutive failures that open the circuit breaker (default 5)
+//	DICTIONARY_BREAKER_TIMEOUT    how long the breaker stays open before probing again (default "30s")
+//	DICTIONARY_OFFLINE_FILE       EJDict formatted file loaded into memory by the offline provider
+//	DICTIONARY_CACHE_SIZE         maximum number of entries cached in memory
+//	DICTIONARY_CACHE_TTL          how long entries are cached in memory (e.g. "24h")
+//	DICTIONARY_CACHE_STORE_TTL    how long entries are cached in the database
+//	DICTIONARY_NEGATIVE_CACHE_TTL how long words that were not found are cached
+func FromEnv(lexicon Store, cacheStore EntryStore) (Provider, error) {
+	names := os.Getenv("DICTIONARY_PROVIDERS")
+	if names == "" {
+		names = "http"
+	}
+
+	var chain Chain
+	for _, name := range strings.Split(names, ",") {
+		switch strings.TrimSpace(name) {
+		case "http":
+			provider, err := httpProviderFromEnv()
+			if err != nil {
+				return nil, err
+			}
+			chain = append(chain, provider)
+		case "offline":
+			path := os.Getenv("DICTIONARY_OFFLINE_FILE")
+			if path == "" {
+				chain = append(chain, NewOfflineProvider(lexicon))
+				continue
+			}
+			store, err := LoadFile(path, "ejdict")
+			if err != nil {
+				return nil, err
+			}
+			chain = append(chain, NewOfflineProvider(store, EnJa))
+		case "fake":
+			chain = append(chain, NewFakeProvider(map[Direction]map[string]string{
+				EnJa: {"example": "(…の)『例』,実例"},
+				JaEn: {"例": "(n) example / instance"},
+			}))
+		default:
+			return nil, fmt.Errorf("unknown dictionary provider: %s", name)
+		}
+	}
+
+	config, err := cacheConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPhraseProvider(NewCachedProvider(chain, cacheStore, config)), nil
+}
+
+// httpProviderFromEnv builds the HTTP provider wrapped in a circuit breaker.
+func httpProviderFromEnv() (Provider, error) {
+	timeout, err := durationFromEnv("DICTIONARY_HTTP_TIMEOUT", 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	retries, err := intFromEnv("DICTIONARY_HTTP_RETRIES", 2)
+	if err != nil {
+		return nil, err
+	}
+	failures, err := intFromEnv("DICTIONARY_BREAKER_FAILURES", 5)
+	if err != nil {
+		return nil, err
+	}
+	openTimeout, err := durationFromEnv("DICTIONARY_BREAKER_TIMEOUT", 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	baseURLs := map[Direction]string{EnJa: DefaultHTTPURL}
+	if url := os.Getenv("DICTIONARY_HTTP_URL"); url != "" {
+		baseURLs[EnJa] = url
+	}
+	if url := os.Getenv("DICTIONARY_HTTP_JAEN_URL"); url != "" {
+		baseURLs[JaEn] = url
+	}
+	if urls := os.Getenv("DICTIONARY_HTTP_URLS"); urls != "" {
+		for _, pair := range strings.Split(urls, ",") {
+			lang, url, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			dir, valid := ParseLang(lang)
+			if !ok || !valid || lang == "" {
+				return nil, fmt.Errorf("invalid DICTIONARY_HTTP_URLS entry: %s", pair)
+			}
+			baseURLs[dir] = url
+		}
+	}
+
+	provider := NewHTTPProvider(baseURLs, timeout, retries)
+	return NewBreaker(provider, failures, openTimeout), nil
+}
+
+func durationFromEnv(key string, fallback time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return d, nil
+}
+
+func intFromEnv(key string, fallback int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid %s: %s", key, value)
+	}
+	return n, nil
+}
+
+func cacheConfigFromEnv() (CacheConfig, error) {
+	config := DefaultCacheConfig
+	var err error
+
+	if config.Size, err = intFromEnv("DICTIONARY_CACHE_SIZE", config.Size); err != nil {
+		return config, err
+	}
+	if config.Size == 0 {
+		return config, fmt.Errorf("invalid DICTIONARY_CACHE_SIZE: 0")
+	}
+	if config.TTL, err = durationFromEnv("DICTIONARY_CACHE_TTL", config.TTL); err != nil {
+		return config, err
+	}
+	if config.StoreTTL, err = durationFromEnv("DICTIONARY_CACHE_STORE_TTL", config.StoreTTL); err != nil {
+		return config, err
+	}
+	if config.NegativeTTL, err = durationFromEnv("DICTIONARY_NEGATIVE_CACHE_TTL", config.NegativeTTL); err != nil {
+		return config, err
+	}
+	if config.LookupTimeout, err = durationFromEnv("DICTIONARY_LOOKUP_TIMEOUT", config.LookupTimeout); err != nil {
+		return config, err
+	}
+	if config.LookupTimeout <= 0 {
+		return config, fmt.Errorf("invalid DICTIONARY_LOOKUP_TIMEOUT: %s", config.LookupTimeout)
+	}
+
+	return config, nil
+}
@@ -0,0 +1,19 @@
+package dictionary
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotFound is returned when a provider has no entry for the word.
+var ErrNotFound = errors.New("word not found")
+
+// Provider looks up the meaning of a word.
+type Provider interface {
+	// Name identifies the provider in logs and configuration.
+	Name() string
+	// Supports reports whether the provider can look up words in dir.
+	Supports(dir Direction) bool
+	// Lookup returns the entry for q, or ErrNotFound if the provider has none.
+	Lookup(ctx context.Context, q Query) (*Entry, error)
+}
@@ -0,0 +1,163 @@
+package dictionary
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Direction is the language direction of a lookup, written as "source-target"
+// with ISO 639-1 codes. A learner's language pair uses the same notation:
+// "de-ja" is a Japanese speaker learning German.
+type Direction string
+
+const (
+	EnJa Direction = "en-ja" // 英和
+	JaEn Direction = "ja-en" // 和英
+)
+
+// DefaultLang is the language pair used when a request does not specify one.
+const DefaultLang = EnJa
+
+var directionPattern = regexp.MustCompile(`^[a-z]{2}-[a-z]{2}$`)
+
+// ParseLang validates a language pair such as "en-ja". 空文字列の場合は DefaultLang を返す
+func ParseLang(value string) (Direction, bool) {
+	if value == "" {
+		return DefaultLang, true
+	}
+	if !directionPattern.MatchString(value) || value[:2] == value[3:] {
+		return "", false
+	}
+	return Direction(value), true
+}
+
+// Source returns the language looked up, e.g. "en" for "en-ja".
+func (d Direction) Source() string {
+	return string(d[:2])
+}
+
+// Target returns the language of the meanings, e.g. "ja" for "en-ja".
+func (d Direction) Target() string {
+	return string(d[3:])
+}
+
+// Reverse returns the opposite direction, e.g. "ja-en" for "en-ja".
+func (d Direction) Reverse() Direction {
+	return Direction(d.Target() + "-" + d.Source())
+}
+
+// ParseDirection validates a lookup direction given by a client for the learner's
+// language pair lang. It accepts lang itself or its reverse; 空文字列の場合は自動判定する
+func ParseDirection(value string, lang Direction, word string) (Direction, bool) {
+	switch value {
+	case string(lang), string(lang.Reverse()):
+		return Direction(value), true
+	case "", "auto":
+		return DetectDirection(word, lang), true
+	default:
+		return "", false
+	}
+}
+
+// DetectDirection returns the reverse of lang when word is written in Japanese
+// and lang has Japanese as its target, otherwise lang.
+func DetectDirection(word string, lang Direction) Direction {
+	if lang.Target() != "ja" {
+		return lang
+	}
+	for _, r := range word {
+		if unicode.In(r, unicode.Hiragana, unicode.Katakana, unicode.Han) || r == 'ー' {
+			return lang.Reverse()
+		}
+	}
+	return lang
+}
+
+// Query is a single dictionary lookup.
+type Query struct {
+	Word      string
+	Direction Direction
+}
+
+// key identifies the query in caches.
+func (q Query) key() string {
+	return string(q.Direction) + ":" + q.Word
+}
+
+// edictPartsOfSpeech maps EDICT part of speech codes onto the names used by ParseEntry.
+var edictPartsOfSpeech = map[string]string{
+	"n":    "名詞",
+	"pn":   "代名詞",
+	"v":    "動詞",
+	"aux":  "助動詞",
+	"adj":  "形容詞",
+	"adv":  "副詞",
+	"prt":  "助詞",
+	"conj": "接続詞",
+	"int":  "間投詞",
+	"exp":  "表現",
+}
+
+// parseEntry parses raw in the format used for dir: EDICT style for lookups
+// from Japanese, EJDict style otherwise.
+func parseEntry(word, raw string, dir Direction) *Entry {
+	if dir.Source() == "ja" {
+		return ParseJaEnEntry(word, raw)
+	}
+	return ParseEntry(word, raw)
+}
+
+// ParseJaEnEntry parses EDICT style glosses ("(n) (1) gloss / (2) gloss") into an Entry
+// whose Headword is the first gloss in the learner's language.
+func ParseJaEnEntry(word, raw string) *Entry {
+	entry := &Entry{
+		Word:          word,
+		PartsOfSpeech: []string{},
+		Senses:        []Sense{},
+		Raw:           raw,
+	}
+
+	seen := map[string]bool{}
+	partOfSpeech := ""
+	for _, gloss := range strings.Split(raw, " / ") {
+		gloss = strings.TrimSpace(gloss)
+		for strings.HasPrefix(gloss, "(") {
+			end := strings.Index(gloss, ")")
+			if end < 0 {
+				break
+			}
+			for _, code := range strings.Split(gloss[1:end], ",") {
+				if pos, ok := edictPartOfSpeech(code); ok {
+					partOfSpeech = pos
+				}
+			}
+			gloss = strings.TrimSpace(gloss[end+1:])
+		}
+		if gloss == "" {
+			continue
+		}
+
+		entry.Senses = append(entry.Senses, Sense{PartOfSpeech: partOfSpeech, Gloss: gloss})
+		if partOfSpeech != "" && !seen[partOfSpeech] {
+			seen[partOfSpeech] = true
+			entry.PartsOfSpeech = append(entry.PartsOfSpeech, partOfSpeech)
+		}
+		if entry.Headword == "" {
+			entry.Headword = strings.TrimPrefix(gloss, "to ")
+		}
+	}
+
+	return entry
+}
+
+// edictPartOfSpeech maps codes such as "v1" or "adj-i" onto a part of speech.
+func edictPartOfSpeech(code string) (string, bool) {
+	code = strings.TrimSpace(code)
+	for prefix, pos := range edictPartsOfSpeech {
+		if code == prefix || strings.HasPrefix(code, prefix+"-") || (prefix == "v" && strings.HasPrefix(code, "v")) {
+			return pos, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,99 @@
+package dictionary
+
+import (
+	"strings"
+)
+
+// Entry is a parsed dictionary entry.
+type Entry struct {
+	Word string `json:"word"`
+	// Headword is the word to record in the learner's word list.
+	// 英和では Word と同じ、和英では最初の英訳になる
+	Headword      string    `json:"headword"`
+	PartsOfSpeech []string  `json:"parts_of_speech"`
+	Senses        []Sense   `json:"senses"`
+	Pronunciation string    `json:"pronunciation,omitempty"`
+	Examples      []Example `json:"examples,omitempty"`
+	// Raw is the unparsed text returned by the provider
+	Raw string `json:"raw"`
+	// Fallback is the head word looked up when a phrase has no entry of its own
+	Fallback string `json:"fallback,omitempty"`
+}
+
+// Sense is a single meaning of a word.
+type Sense struct {
+	PartOfSpeech string `json:"part_of_speech,omitempty"`
+	Gloss        string `json:"gloss"`
+}
+
+// Example is an example sentence and its translation.
+type Example struct {
+	Text        string `json:"text"`
+	Translation string `json:"translation"`
+}
+
+// 品詞の略号（EJDict・excelapi で使われる〈〉内の表記）
+var partOfSpeechMarkers = map[string]string{
+	"名": "名詞",
+	"C": "名詞",
+	"U": "名詞",
+	"代": "代名詞",
+	"動": "動詞",
+	"他": "動詞",
+	"自": "動詞",
+	"助": "助動詞",
+	"形": "形容詞",
+	"副": "副詞",
+	"前": "前置詞",
+	"接": "接続詞",
+	"冠": "冠詞",
+	"間": "間投詞",
+}
+
+// ParseEntry parses the "sense / sense / ..." text used by EJDict and excelapi into an Entry.
+func ParseEntry(word, raw string) *Entry {
+	entry := &Entry{
+		Word:          word,
+		Headword:      word,
+		PartsOfSpeech: []string{},
+		Senses:        []Sense{},
+		Raw:           raw,
+	}
+
+	seen := map[string]bool{}
+	for _, part := range strings.Split(raw, " / ") {
+		sense := parseSense(part)
+		if sense.Gloss == "" {
+			continue
+		}
+		entry.Senses = append(entry.Senses, sense)
+		if sense.PartOfSpeech != "" && !seen[sense.PartOfSpeech] {
+			seen[sense.PartOfSpeech] = true
+			entry.PartsOfSpeech = append(entry.PartsOfSpeech, sense.PartOfSpeech)
+		}
+	}
+
+	return entry
+}
+
+// parseSense strips leading 〈…〉 markers from text and maps them to a part of speech.
+func parseSense(text string) Sense {
+	var sense Sense
+
+	text = strings.TrimSpace(text)
+	for strings.HasPrefix(text, "〈") {
+		end := strings.Index(text, "〉")
+		if end < 0 {
+			break
+		}
+		marker := text[len("〈"):end]
+		if pos, ok := partOfSpeechMarkers[marker]; ok && sense.PartOfSpeech == "" {
+			sense.PartOfSpeech = pos
+		}
+		text = strings.TrimSpace(text[end+len("〉"):])
+	}
+
+	// 強調記号『』は表示用なので取り除く
+	sense.Gloss = strings.NewReplacer("『", "", "』", "").Replace(text)
+	return sense
+}
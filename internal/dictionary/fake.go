@@ -0,0 +1,29 @@
+package dictionary
+
+import "context"
+
+// FakeProvider returns fixed entries. ローカル開発やテストで外部APIを呼ばないために使う
+type FakeProvider struct {
+	Entries map[Direction]map[string]string
+}
+
+func NewFakeProvider(entries map[Direction]map[string]string) *FakeProvider {
+	return &FakeProvider{Entries: entries}
+}
+
+func (p *FakeProvider) Name() string {
+	return "fake"
+}
+
+func (p *FakeProvider) Supports(dir Direction) bool {
+	_, ok := p.Entries[dir]
+	return ok
+}
+
+func (p *FakeProvider) Lookup(_ context.Context, q Query) (*Entry, error) {
+	meanings, found := p.Entries[q.Direction][q.Word]
+	if !found {
+		return nil, ErrNotFound
+	}
+	return parseEntry(q.Word, meanings, q.Direction), nil
+}
@@ -0,0 +1,109 @@
+package dictionary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultHTTPURL is the English-Japanese dictionary API used when DICTIONARY_HTTP_URL is not set.
+const DefaultHTTPURL = "https://api.excelapi.org/dictionary/enja"
+
+// HTTPProvider looks up words from an excelapi compatible dictionary API.
+type HTTPProvider struct {
+	// BaseURLs holds the API endpoint for each supported direction
+	BaseURLs map[Direction]string
+	Client   *http.Client
+	// Retries is the number of extra attempts after a failed request
+	Retries int
+	// Backoff is the wait before the first retry, doubled on each further retry
+	Backoff time.Duration
+}
+
+func NewHTTPProvider(baseURLs map[Direction]string, timeout time.Duration, retries int) *HTTPProvider {
+	return &HTTPProvider{
+		BaseURLs: baseURLs,
+		Client:   &http.Client{Timeout: timeout},
+		Retries:  retries,
+		Backoff:  200 * time.Millisecond,
+	}
+}
+
+// errRetryable marks failures worth retrying, such as network errors and 5xx responses.
+var errRetryable = errors.New("retryable")
+
+func (p *HTTPProvider) Name() string {
+	return "http"
+}
+
+func (p *HTTPProvider) Supports(dir Direction) bool {
+	_, ok := p.BaseURLs[dir]
+	return ok
+}
+
+func (p *HTTPProvider) Lookup(ctx context.Context, q Query) (*Entry, error) {
+	backoff := p.Backoff
+	for attempt := 0; ; attempt++ {
+		entry, err := p.lookupOnce(ctx, q)
+		if err == nil || !errors.Is(err, errRetryable) || attempt >= p.Retries {
+			return entry, err
+		}
+
+		log.Printf("dictionary API attempt %d for %q failed, retrying in %s: %v", attempt+1, q.Word, backoff, err)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (p *HTTPProvider) lookupOnce(ctx context.Context, q Query) (*Entry, error) {
+	baseURL, ok := p.BaseURLs[q.Direction]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	// 辞書APIにリクエストを送信
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"?word="+url.QueryEscape(q.Word), nil)
+	if err != nil {
+		return nil, fmt.Errorf("辞書APIリクエスト作成失敗: %w", err)
+	}
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("辞書APIリクエスト失敗: %w: %w", errRetryable, err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Printf("response body close error: %v", err)
+		}
+	}()
+
+	if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+		return nil, fmt.Errorf("辞書APIステータスエラー: %w: %d", errRetryable, resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("辞書APIステータスエラー: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("辞書APIレスポンス読み取りエラー: %w: %w", errRetryable, err)
+	}
+
+	// 見つからない単語には空のレスポンスが返る
+	meanings := strings.TrimSpace(string(body))
+	if meanings == "" {
+		return nil, ErrNotFound
+	}
+
+	return parseEntry(q.Word, meanings, q.Direction), nil
+}
@@ -0,0 +1,78 @@
+package dictionary
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// cacheItem is a cached lookup result; a nil entry records that the word was not found.
+type cacheItem struct {
+	key       string
+	entry     *Entry
+	expiresAt time.Time
+}
+
+// LRU is a size bounded in-memory cache whose items expire after their TTL.
+type LRU struct {
+	capacity int
+
+	mu    sync.Mutex
+	order *list.List
+	items map[string]*list.Element
+}
+
+func NewLRU(capacity int) *LRU {
+	return &LRU{
+		capacity: capacity,
+		order:    list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+// Get returns the cached item for key if it has not expired.
+func (c *LRU) Get(key string, now time.Time) (cacheItem, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, found := c.items[key]
+	if !found {
+		return cacheItem{}, false
+	}
+
+	item := elem.Value.(cacheItem)
+	if !now.Before(item.expiresAt) {
+		c.order.Remove(elem)
+		delete(c.items, key)
+		return cacheItem{}, false
+	}
+
+	c.order.MoveToFront(elem)
+	return item, true
+}
+
+// Add stores item, evicting the least recently used item when the cache is full.
+func (c *LRU) Add(item cacheItem) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, found := c.items[item.key]; found {
+		elem.Value = item
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.items[item.key] = c.order.PushFront(item)
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(cacheItem).key)
+	}
+}
+
+// Len returns the number of cached items, including expired ones not yet evicted.
+func (c *LRU) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
@@ -0,0 +1,100 @@
+package dictionary
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRU(t *testing.T) {
+	type op struct {
+		add string // 追加するキー。空なら get を行う
+		get string
+		// at is the time of the operation after the start
+		at        time.Duration
+		wantFound bool
+	}
+
+	tests := []struct {
+		name     string
+		capacity int
+		ops      []op
+		wantLen  int
+	}{
+		{
+			name:     "evicts the least recently added item",
+			capacity: 2,
+			ops: []op{
+				{add: "a"}, {add: "b"}, {add: "c"},
+				{get: "a", wantFound: false},
+				{get: "b", wantFound: true},
+				{get: "c", wantFound: true},
+			},
+			wantLen: 2,
+		},
+		{
+			name:     "get makes an item recently used",
+			capacity: 2,
+			ops: []op{
+				{add: "a"}, {add: "b"},
+				{get: "a", wantFound: true},
+				{add: "c"},
+				{get: "b", wantFound: false},
+				{get: "a", wantFound: true},
+				{get: "c", wantFound: true},
+			},
+			wantLen: 2,
+		},
+		{
+			name:     "adding an existing key replaces it",
+			capacity: 2,
+			ops: []op{
+				{add: "a"}, {add: "b"}, {add: "a"}, {add: "c"},
+				{get: "a", wantFound: true},
+				{get: "b", wantFound: false},
+			},
+			wantLen: 2,
+		},
+		{
+			name:     "expired items are not returned",
+			capacity: 2,
+			ops: []op{
+				{add: "a"},
+				{get: "a", at: time.Minute - time.Second, wantFound: true},
+				{get: "a", at: time.Minute, wantFound: false},
+			},
+			wantLen: 0,
+		},
+		{
+			name:     "missing key",
+			capacity: 1,
+			ops: []op{
+				{get: "a", wantFound: false},
+			},
+			wantLen: 0,
+		},
+	}
+
+	start := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lru := NewLRU(tt.capacity)
+			for i, op := range tt.ops {
+				now := start.Add(op.at)
+				if op.add != "" {
+					lru.Add(cacheItem{key: op.add, expiresAt: now.Add(time.Minute)})
+					continue
+				}
+				item, found := lru.Get(op.get, now)
+				if found != op.wantFound {
+					t.Errorf("op %d: Get(%q) found = %v, want %v", i, op.get, found, op.wantFound)
+				}
+				if found && item.key != op.get {
+					t.Errorf("op %d: Get(%q) returned %q", i, op.get, item.key)
+				}
+			}
+			if got := lru.Len(); got != tt.wantLen {
+				t.Errorf("Len() = %d, want %d", got, tt.wantLen)
+			}
+		})
+	}
+}
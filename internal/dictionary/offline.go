@@ -0,0 +1,219 @@
+package dictionary
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Store holds dictionary entries available without network access.
+type Store interface {
+	// LookupEntry returns the meaning of word in dir and whether it was found.
+	LookupEntry(ctx context.Context, word string, dir Direction) (string, bool, error)
+}
+
+// OfflineProvider answers lookups from a local Store, either the imported
+// lexicon table or a dictionary file loaded into memory.
+type OfflineProvider struct {
+	store      Store
+	directions []Direction
+}
+
+// NewOfflineProvider returns a provider answering lookups in directions from store.
+// directions を省略した場合はすべての方向を扱う（インポートした辞書にある方向だけ見つかる）
+func NewOfflineProvider(store Store, directions ...Direction) *OfflineProvider {
+	return &OfflineProvider{store: store, directions: directions}
+}
+
+func (p *OfflineProvider) Name() string {
+	return "offline"
+}
+
+func (p *OfflineProvider) Supports(dir Direction) bool {
+	return len(p.directions) == 0 || slices.Contains(p.directions, dir)
+}
+
+func (p *OfflineProvider) Lookup(ctx context.Context, q Query) (*Entry, error) {
+	meanings, found, err := p.store.LookupEntry(ctx, q.Word, q.Direction)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrNotFound
+	}
+	return parseEntry(q.Word, meanings, q.Direction), nil
+}
+
+// MemoryStore is a Store kept entirely in memory, holding entries of a single direction.
+type MemoryStore map[string]string
+
+func (m MemoryStore) LookupEntry(_ context.Context, word string, _ Direction) (string, bool, error) {
+	meanings, found := m[strings.ToLower(word)]
+	return meanings, found, nil
+}
+
+// LoadEJDict reads an EJDict formatted file ("headword\tmeaning" per line).
+// 見出し語が "color,colour" のように複数ある場合はそれぞれを登録する
+func LoadEJDict(r io.Reader) (MemoryStore, error) {
+	store := MemoryStore{}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		headwords, meaning, ok := strings.Cut(scanner.Text(), "\t")
+		if !ok || meaning == "" {
+			continue
+		}
+		for _, headword := range strings.Split(headwords, ",") {
+			headword = strings.ToLower(strings.TrimSpace(headword))
+			if headword == "" {
+				continue
+			}
+			if existing, found := store[headword]; found {
+				store[headword] = existing + " / " + meaning
+			} else {
+				store[headword] = meaning
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read dictionary file: %w", err)
+	}
+
+	return store, nil
+}
+
+// LoadEDICT reads an EDICT formatted file ("漢字 [かな] /(n) gloss/gloss/" per line)
+// and inverts it into an English-Japanese dictionary keyed by gloss.
+func LoadEDICT(r io.Reader) (MemoryStore, error) {
+	store := MemoryStore{}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		head, rest, ok := strings.Cut(line, "/")
+		if !ok {
+			continue
+		}
+
+		// "漢字 [かな]" を "漢字(かな)" にまとめる
+		japanese := strings.TrimSpace(head)
+		if kanji, kana, ok := strings.Cut(japanese, " ["); ok {
+			japanese = kanji + "(" + strings.TrimSuffix(kana, "]") + ")"
+		}
+
+		for _, gloss := range strings.Split(rest, "/") {
+			headword := edictHeadword(gloss)
+			if headword == "" {
+				continue
+			}
+			if existing, found := store[headword]; found {
+				if !strings.Contains(existing, japanese) {
+					store[headword] = existing + ", " + japanese
+				}
+			} else {
+				store[headword] = japanese
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read dictionary file: %w", err)
+	}
+
+	return store, nil
+}
+
+// edictHeadword normalises an EDICT gloss such as "(n) (1) to eat" into "eat".
+// 長い説明文は見出し語として使わない
+func edictHeadword(gloss string) string {
+	gloss = strings.TrimSpace(gloss)
+	if strings.HasPrefix(gloss, "EntL") {
+		return ""
+	}
+	for strings.HasPrefix(gloss, "(") {
+		end := strings.Index(gloss, ")")
+		if end < 0 {
+			return ""
+		}
+		gloss = strings.TrimSpace(gloss[end+1:])
+	}
+	gloss = strings.TrimPrefix(strings.ToLower(gloss), "to ")
+	if gloss == "" || strings.Count(gloss, " ") > 3 {
+		return ""
+	}
+	return gloss
+}
+
+// LoadEDICTJaEn reads an EDICT formatted file as a Japanese-English dictionary
+// keyed by both the kanji and the kana spelling. Glosses are joined with " / ".
+func LoadEDICTJaEn(r io.Reader) (MemoryStore, error) {
+	store := MemoryStore{}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		head, rest, ok := strings.Cut(scanner.Text(), "/")
+		if !ok {
+			continue
+		}
+
+		var glosses []string
+		for _, gloss := range strings.Split(rest, "/") {
+			gloss = strings.TrimSpace(gloss)
+			if gloss == "" || strings.HasPrefix(gloss, "EntL") {
+				continue
+			}
+			glosses = append(glosses, gloss)
+		}
+		if len(glosses) == 0 {
+			continue
+		}
+		meaning := strings.Join(glosses, " / ")
+
+		kanji, kana, _ := strings.Cut(strings.TrimSpace(head), " [")
+		for _, headword := range []string{kanji, strings.TrimSuffix(kana, "]")} {
+			if headword == "" {
+				continue
+			}
+			if existing, found := store[headword]; found {
+				store[headword] = existing + " / " + meaning
+			} else {
+				store[headword] = meaning
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read dictionary file: %w", err)
+	}
+
+	return store, nil
+}
+
+// LoadFile reads a dictionary file of the given format ("ejdict", "edict"
+// or "edict-jaen") from path. EJDict 形式は英語以外の "見出し語<TAB>意味" のファイルにも使える
+// ファイルはUTF-8である必要がある
+func LoadFile(path, format string) (MemoryStore, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dictionary file: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	switch format {
+	case "ejdict":
+		return LoadEJDict(f)
+	case "edict":
+		return LoadEDICT(f)
+	case "edict-jaen":
+		return LoadEDICTJaEn(f)
+	default:
+		return nil, fmt.Errorf("unknown dictionary format: %s", format)
+	}
+}
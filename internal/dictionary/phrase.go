@@ -0,0 +1,86 @@
+package dictionary
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"tsumitan/internal/normalize"
+)
+
+// PhraseProvider looks up multi-word expressions such as "give up" or "in spite of".
+// The whole phrase is queried first; when it has no entry of its own, the phrase is
+// looked up through its head word and only the senses mentioning the phrase are kept.
+// If no sense mentions it, as with the Japanese glosses of en-ja, the phrase is not found.
+type PhraseProvider struct {
+	provider Provider
+}
+
+func NewPhraseProvider(provider Provider) *PhraseProvider {
+	return &PhraseProvider{provider: provider}
+}
+
+func (p *PhraseProvider) Name() string {
+	return p.provider.Name()
+}
+
+func (p *PhraseProvider) Supports(dir Direction) bool {
+	return p.provider.Supports(dir)
+}
+
+func (p *PhraseProvider) Lookup(ctx context.Context, q Query) (*Entry, error) {
+	entry, err := p.provider.Lookup(ctx, q)
+	if !strings.Contains(q.Word, " ") {
+		return entry, err
+	}
+	// 辞書APIの障害などは見出し語で引き直さない
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return entry, err
+	}
+	if err == nil && len(entry.Senses) > 0 {
+		return entry, nil
+	}
+
+	head := normalize.Head(q.Word, q.Direction.Source())
+	headEntry, headErr := p.provider.Lookup(ctx, Query{Word: head, Direction: q.Direction})
+	if headErr != nil {
+		return entry, err
+	}
+	if phrase := phraseEntry(q.Word, head, headEntry); phrase != nil {
+		return phrase, nil
+	}
+	return nil, ErrNotFound
+}
+
+// phraseEntry builds the entry of phrase from the senses of its head word that mention it,
+// or returns nil if there are none.
+// キャッシュされている見出し語のエントリは変更しない
+func phraseEntry(phrase, head string, headEntry *Entry) *Entry {
+	var senses []Sense
+	for _, sense := range headEntry.Senses {
+		if strings.Contains(strings.ToLower(sense.Gloss), phrase) {
+			senses = append(senses, sense)
+		}
+	}
+	// 見出し語の意味をそのまま返すとフレーズの意味と区別できないため、見つからないものとする
+	if len(senses) == 0 {
+		return nil
+	}
+
+	entry := *headEntry
+	entry.Word = phrase
+	entry.Fallback = head
+	if headEntry.Headword == head {
+		entry.Headword = phrase
+	}
+	entry.Senses = senses
+	return &entry
+}
+
+// Health reports the state of the wrapped provider.
+func (p *PhraseProvider) Health() map[string]string {
+	if reporter, ok := p.provider.(HealthReporter); ok {
+		return reporter.Health()
+	}
+	return map[string]string{}
+}
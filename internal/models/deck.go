@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+)
+
+// Deck はユーザーが作る単語帳（TOEIC 対策、読んでいる小説など）。単語は複数のデッキに入れられる
+type Deck struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	UserID      string    `gorm:"uniqueIndex:idx_decks_user_name" json:"user_id"`
+	Name        string    `gorm:"uniqueIndex:idx_decks_user_name" json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// DeckWord はデッキと単語の関連
+type DeckWord struct {
+	DeckID    uint      `gorm:"primaryKey" json:"deck_id"`
+	UserID    string    `gorm:"index:idx_deck_words_user_word" json:"user_id"`
+	Word      string    `gorm:"primaryKey;index:idx_deck_words_user_word" json:"word"`
+	Lang      string    `gorm:"primaryKey;index:idx_deck_words_user_word" json:"lang"`
+	CreatedAt time.Time `json:"created_at"`
+}
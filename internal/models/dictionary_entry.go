@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+)
+
+// DictionaryEntry は辞書の検索結果の永続キャッシュ
+type DictionaryEntry struct {
+	Key string `gorm:"primaryKey" json:"key"`
+	// Entry is the JSON encoded dictionary.Entry, empty when NotFound
+	Entry string `json:"entry"`
+	// NotFound caches that no provider had the word (negative caching)
+	NotFound  bool      `json:"not_found"`
+	ExpiresAt time.Time `gorm:"index" json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,20 @@
+package models
+
+// ExampleSentence は対訳コーパス（Tatoeba など）からインポートした例文と訳
+type ExampleSentence struct {
+	ID uint `gorm:"primaryKey" json:"id"`
+	// Lang is the language pair of the sentence and its translation, e.g. "en-ja"
+	Lang string `gorm:"index:idx_example_sentences_lang_length" json:"lang"`
+	// Source is the corpus the sentence was imported from, e.g. "tatoeba"
+	Source      string `gorm:"index" json:"source"`
+	Text        string `json:"text"`
+	Translation string `json:"translation"`
+	// Length is the number of words of Text, used to prefer short examples
+	Length int `gorm:"index:idx_example_sentences_lang_length" json:"length"`
+}
+
+// ExampleToken は例文に含まれる見出し語（原形）の索引
+type ExampleToken struct {
+	Token      string `gorm:"primaryKey" json:"token"`
+	SentenceID uint   `gorm:"primaryKey;index" json:"sentence_id"`
+}
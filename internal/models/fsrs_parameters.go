@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+)
+
+// FSRSParameters はユーザーごとに最適化したFSRSのパラメータ
+type FSRSParameters struct {
+	UserID  string    `gorm:"primaryKey" json:"user_id"`
+	Weights []float64 `gorm:"serializer:json" json:"weights"`
+	// 最適化に使用した復習数と、そのときの損失
+	ReviewCount int       `json:"review_count"`
+	Loss        float64   `json:"loss"`
+	FittedAt    time.Time `json:"fitted_at"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
@@ -0,0 +1,11 @@
+package models
+
+// LexiconEntry はオフライン辞書（EJDict などからインポート）の見出し語
+type LexiconEntry struct {
+	Headword string `gorm:"primaryKey" json:"headword"`
+	// Direction is the lookup direction, "en-ja" or "ja-en"
+	Direction string `gorm:"primaryKey;default:en-ja" json:"direction"`
+	// Source is the dataset the entry was imported from, e.g. "ejdict"
+	Source  string `gorm:"primaryKey" json:"source"`
+	Meaning string `json:"meaning"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+)
+
+// ReviewEvent は1回の復習の記録
+type ReviewEvent struct {
+	ID                   uint      `gorm:"primaryKey" json:"id"`
+	UserID               string    `gorm:"index:idx_review_events_user_word" json:"user_id"`
+	Word                 string    `gorm:"index:idx_review_events_user_word" json:"word"`
+	Lang                 string    `gorm:"index:idx_review_events_user_word;default:en-ja" json:"lang"`
+	ReviewedAt           time.Time `json:"reviewed_at"`
+	Grade                string    `json:"grade"`
+	ResponseTimeMs       int       `json:"response_time_ms"`
+	PreviousIntervalDays int       `json:"previous_interval_days"`
+	NewIntervalDays      int       `json:"new_interval_days"`
+	Device               string    `json:"device"`
+	CreatedAt            time.Time `json:"created_at"`
+}
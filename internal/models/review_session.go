@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+)
+
+// ReviewSession は復習期限を迎えた単語をまとめて復習する1回のセッション。中断しても続きから再開できる
+type ReviewSession struct {
+	ID     uint   `gorm:"primaryKey" json:"id"`
+	UserID string `gorm:"index" json:"user_id"`
+	// Lang is the language pair of the session, "" when it mixes every pair
+	Lang       string     `json:"lang"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+}
+
+// ReviewSessionCard はセッションで出題する単語とその回答
+type ReviewSessionCard struct {
+	SessionID uint   `gorm:"primaryKey" json:"session_id"`
+	Word      string `gorm:"primaryKey" json:"word"`
+	Lang      string `gorm:"primaryKey" json:"lang"`
+	// Position is the order of the card in the session, from 0
+	Position int `json:"position"`
+	// FirstReview is true when the word had never been reviewed when the session started
+	FirstReview bool `json:"first_review"`
+	// Grade is empty until the card is answered
+	Grade          string     `json:"grade"`
+	ResponseTimeMs int        `json:"response_time_ms"`
+	AnsweredAt     *time.Time `json:"answered_at"`
+}
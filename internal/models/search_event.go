@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+)
+
+// SearchEvent は1回の単語検索の記録
+type SearchEvent struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	UserID     string    `gorm:"index:idx_search_events_user_word" json:"user_id"`
+	Word       string    `gorm:"index:idx_search_events_user_word" json:"word"`
+	Lang       string    `gorm:"index:idx_search_events_user_word;default:en-ja" json:"lang"`
+	SearchedAt time.Time `json:"searched_at"`
+	// SurfaceForm is the word as the user typed it, before normalisation
+	SurfaceForm string `json:"surface_form"`
+	SourceURL   string `json:"source_url"`
+	SourceTitle string `json:"source_title"`
+	// 単語が出現した文
+	Sentence  string    `json:"sentence"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+)
+
+// Tag は単語に付けるラベル（「動詞」「第3章」など）。単語には複数のタグを付けられる
+type Tag struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	UserID      string    `gorm:"uniqueIndex:idx_tags_user_name" json:"user_id"`
+	Name        string    `gorm:"uniqueIndex:idx_tags_user_name" json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// WordTag はタグと単語の関連
+type WordTag struct {
+	TagID     uint      `gorm:"primaryKey" json:"tag_id"`
+	UserID    string    `gorm:"index:idx_word_tags_user_word" json:"user_id"`
+	Word      string    `gorm:"primaryKey;index:idx_word_tags_user_word" json:"word"`
+	Lang      string    `gorm:"primaryKey;index:idx_word_tags_user_word" json:"lang"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -2,14 +2,45 @@ package models
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type Word struct {
-	UserID       string    `gorm:"primaryKey" json:"user_id"`
-	Word         string    `gorm:"primaryKey" json:"word"`
-	SearchCount  int       `json:"search_count"`
-	ReviewCount  int       `json:"review_count"`
-	LastReviewed time.Time `json:"last_reviewed"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	UserID string `gorm:"primaryKey" json:"user_id"`
+	Word   string `gorm:"primaryKey" json:"word"`
+	// Lang is the language pair being learned, e.g. "en-ja"
+	Lang string `gorm:"primaryKey;default:en-ja" json:"lang"`
+	// Kind is "word", "phrasal_verb" or "phrase"
+	Kind string `gorm:"default:word" json:"kind"`
+	// 同梱の単語リストによる頻度順位（不明な場合は0）と推定 CEFR レベル
+	FrequencyRank int    `gorm:"default:0" json:"frequency_rank"`
+	Level         string `gorm:"index" json:"level"`
+	// 学習者が書いたメモ（Markdown）・語呂合わせ・辞書の意味の代わりに表示する自分の定義
+	Notes            string    `gorm:"type:text" json:"notes"`
+	Mnemonic         string    `json:"mnemonic"`
+	CustomDefinition string    `json:"custom_definition"`
+	SearchCount      int       `json:"search_count"`
+	ReviewCount      int       `json:"review_count"`
+	LapseCount       int       `gorm:"default:0" json:"lapse_count"`
+	LastReviewed     time.Time `json:"last_reviewed"`
+	// 直近の復習で回答にかかった時間（ミリ秒、不明な場合は0）
+	LastResponseTimeMs int `gorm:"default:0" json:"last_response_time_ms"`
+	// 復習スケジュール（internal/scheduler が更新する）
+	EaseFactor   float64   `gorm:"default:2.5" json:"ease_factor"`
+	IntervalDays int       `gorm:"default:0" json:"interval_days"`
+	Repetitions  int       `gorm:"default:0" json:"repetitions"`
+	DueAt        time.Time `gorm:"index" json:"due_at"`
+	Stability    float64   `gorm:"default:0" json:"stability"`
+	Difficulty   float64   `gorm:"default:0" json:"difficulty"`
+	// 習得済みとして復習キューから外した日時（統計には残す）
+	ArchivedAt *time.Time `gorm:"index" json:"archived_at"`
+	// 復習キューの調整: 保留中は出さない、BuriedUntil までは出さない、スター付きは先に出す
+	Suspended   bool       `gorm:"default:false" json:"suspended"`
+	BuriedUntil *time.Time `json:"buried_until"`
+	Starred     bool       `gorm:"default:false" json:"starred"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	// ゴミ箱に入れた日時。cmd/purge-trash が一定期間後に完全に削除する
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
 }
@@ -0,0 +1,12 @@
+package models
+
+// WordRelation は WordNet などからインポートした英単語どうしの関係（類義語・反意語など）
+type WordRelation struct {
+	Word string `gorm:"primaryKey" json:"word"`
+	// Relation is "synonym", "antonym", "hypernym" or "derivation"
+	Relation string `gorm:"primaryKey" json:"relation"`
+	Related  string `gorm:"primaryKey" json:"related"`
+	// Source is the dataset the relation was imported from, e.g. "wordnet"
+	Source       string `gorm:"primaryKey" json:"source"`
+	PartOfSpeech string `json:"part_of_speech"`
+}
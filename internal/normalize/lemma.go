@@ -0,0 +1,337 @@
+package normalize
+
+import "strings"
+
+// irregularForms maps inflected English forms that the suffix rules cannot handle onto their lemma.
+var irregularForms = map[string]string{
+	// be / have / do / go
+	"am": "be", "is": "be", "are": "be", "was": "be", "were": "be", "been": "be", "being": "be",
+	"has": "have", "had": "have", "having": "have",
+	"does": "do", "did": "do", "done": "do",
+	"goes": "go", "went": "go", "gone": "go",
+	// 不規則動詞
+	"arose": "arise", "arisen": "arise",
+	"ate": "eat", "eaten": "eat",
+	"awoke": "awake", "awoken": "awake",
+	"beaten": "beat",
+	"became": "become",
+	"began":  "begin", "begun": "begin",
+	"bent":   "bend",
+	"bitten": "bite",
+	"bled":   "bleed",
+	"blew":   "blow", "blown": "blow",
+	"broke": "break", "broken": "break",
+	"bred":    "breed",
+	"brought": "bring",
+	"built":   "build",
+	"burnt":   "burn",
+	"bought":  "buy",
+	"caught":  "catch",
+	"chose":   "choose", "chosen": "choose",
+	"came":  "come",
+	"crept": "creep",
+	"dealt": "deal",
+	"dug":   "dig",
+	"drew":  "draw", "drawn": "draw",
+	"dreamt": "dream",
+	"drank":  "drink", "drunk": "drink",
+	"drove": "drive", "driven": "drive",
+	"fell": "fall", "fallen": "fall",
+	"fed":    "feed",
+	"felt":   "feel",
+	"fought": "fight",
+	"fled":   "flee",
+	"flew":   "fly", "flown": "fly",
+	"forbade": "forbid", "forbidden": "forbid",
+	"forgot": "forget", "forgotten": "forget",
+	"forgave": "forgive", "forgiven": "forgive",
+	"froze": "freeze", "frozen": "freeze",
+	"got": "get", "gotten": "get",
+	"gave": "give", "given": "give",
+	"grew": "grow", "grown": "grow",
+	"hung":  "hang",
+	"heard": "hear",
+	"hid":   "hide", "hidden": "hide",
+	"held":  "hold",
+	"kept":  "keep",
+	"knelt": "kneel",
+	"knew":  "know", "known": "know",
+	"laid":   "lay",
+	"led":    "lead",
+	"leapt":  "leap",
+	"learnt": "learn",
+	"lent":   "lend",
+	"lain":   "lie", "lying": "lie",
+	"lost":   "lose",
+	"made":   "make",
+	"meant":  "mean",
+	"met":    "meet",
+	"paid":   "pay",
+	"proved": "prove", "proven": "prove",
+	"ran":  "run",
+	"rang": "ring", "rung": "ring",
+	"rode": "ride", "ridden": "ride",
+	"risen":  "rise",
+	"said":   "say",
+	"seen":   "see",
+	"sought": "seek",
+	"sold":   "sell",
+	"sent":   "send",
+	"shook":  "shake", "shaken": "shake",
+	"shone":  "shine",
+	"shot":   "shoot",
+	"showed": "show", "shown": "show",
+	"shrank": "shrink", "shrunk": "shrink",
+	"sang": "sing", "sung": "sing",
+	"sank": "sink", "sunk": "sink",
+	"sat":   "sit",
+	"slept": "sleep",
+	"slid":  "slide",
+	"spoke": "speak", "spoken": "speak",
+	"sped":   "speed",
+	"spent":  "spend",
+	"spun":   "spin",
+	"spat":   "spit",
+	"stood":  "stand",
+	"stolen": "steal",
+	"stuck":  "stick",
+	"stung":  "sting",
+	"struck": "strike", "stricken": "strike",
+	"strove": "strive", "striven": "strive",
+	"swore": "swear", "sworn": "swear",
+	"swept": "sweep",
+	"swam":  "swim", "swum": "swim",
+	"swung": "swing",
+	"took":  "take", "taken": "take",
+	"taught": "teach",
+	"tore":   "tear", "torn": "tear",
+	"told":    "tell",
+	"thought": "think",
+	"threw":   "throw", "thrown": "throw",
+	"understood": "understand",
+	"woke":       "wake", "woken": "wake",
+	"wore": "wear", "worn": "wear",
+	"wept":     "weep",
+	"won":      "win",
+	"withdrew": "withdraw", "withdrawn": "withdraw",
+	"wrote": "write", "written": "write",
+	"dying": "die", "tying": "tie",
+	// 不規則な複数形
+	"children": "child", "men": "man", "women": "woman", "people": "person",
+	"feet": "foot", "teeth": "tooth", "geese": "goose", "mice": "mouse",
+	"oxen":   "ox",
+	"knives": "knife", "wives": "wife", "lives": "life", "leaves": "leaf",
+	"wolves": "wolf", "halves": "half", "shelves": "shelf", "selves": "self",
+	"thieves": "thief", "loaves": "loaf", "calves": "calf", "elves": "elf",
+	"criteria": "criterion", "phenomena": "phenomenon",
+	"analyses": "analysis", "crises": "crisis", "theses": "thesis",
+	"hypotheses": "hypothesis", "diagnoses": "diagnosis",
+	"cacti": "cactus", "fungi": "fungus", "stimuli": "stimulus", "nuclei": "nucleus",
+	"buses": "bus", "gases": "gas", "lenses": "lens", "bonuses": "bonus",
+	"viruses": "virus", "campuses": "campus", "statuses": "status", "quizzes": "quiz",
+	"movies": "movie", "cookies": "cookie", "zombies": "zombie", "calories": "calorie",
+	"rookies": "rookie", "prairies": "prairie", "brownies": "brownie",
+	"shoes": "shoe", "toes": "toe", "canoes": "canoe", "foes": "foe",
+	"aches": "ache", "headaches": "headache",
+}
+
+// lemmas lists words that look inflected but are already dictionary forms.
+var lemmas = map[string]bool{
+	// -s
+	"this": true, "thus": true, "yes": true, "its": true, "his": true, "hers": true,
+	"ours": true, "yours": true, "theirs": true, "always": true, "perhaps": true,
+	"whereas": true, "towards": true, "afterwards": true, "sometimes": true, "besides": true,
+	"news": true, "series": true, "species": true, "means": true, "headquarters": true,
+	"physics": true, "mathematics": true, "economics": true, "politics": true,
+	"ethics": true, "athletics": true, "linguistics": true, "electronics": true,
+	"chaos": true, "canvas": true, "atlas": true, "alias": true, "bias": true,
+	"christmas": true, "diabetes": true, "measles": true, "clothes": true, "lens": true,
+	"overseas": true, "themselves": true, "ourselves": true, "yourselves": true,
+	"trousers": true, "scissors": true, "pants": true, "jeans": true,
+	// -ing
+	"morning": true, "evening": true, "ceiling": true, "during": true, "nothing": true,
+	"something": true, "anything": true, "everything": true, "wedding": true,
+	"pudding": true, "darling": true, "sibling": true, "awning": true,
+	"interesting": true, "exciting": true, "amazing": true, "boring": true,
+	"surprising": true, "charming": true, "outstanding": true, "willing": true,
+	// -ed
+	"need": true, "seed": true, "feed": true, "weed": true, "deed": true, "heed": true,
+	"reed": true, "speed": true, "bleed": true, "breed": true, "greed": true,
+	"exceed": true, "proceed": true, "succeed": true, "indeed": true,
+	"hundred": true, "sacred": true, "naked": true, "wicked": true, "rugged": true,
+	"ragged": true, "beloved": true, "kindred": true,
+	"tired": true, "ashamed": true,
+}
+
+// silentEStems are stems of verbs ending in a silent e that the rules in needsSilentE cannot tell
+// apart from verbs without one, because only the stress differs (ignoring but colouring).
+var silentEStems = map[string]bool{
+	"chang": true, "arrang": true, "exchang": true, "challeng": true,
+	"ignor": true, "explor": true, "restor": true, "ador": true, "implor": true, "deplor": true,
+	"complet": true, "delet": true, "compet": true,
+	"invit": true, "excit": true, "unit": true, "ignit": true, "recit": true,
+	"accus": true, "excus": true, "refus": true, "confus": true, "amus": true, "abus": true,
+	"becom": true, "overcom": true, "welcom": true, "inflam": true,
+	"invok": true, "provok": true, "evok": true, "escap": true,
+}
+
+// silentESuffixes end the stems of longer verbs that take a silent e.
+var silentESuffixes = []string{"ag", "ut", "ud", "os", "is", "id", "ib", "in", "ar", "um"}
+
+// Lemma returns the dictionary form of an English word written in lower case,
+// e.g. "running" → "run", "studies" → "study" and "ran" → "run".
+// It works without a dictionary, so it uses a list of irregular forms followed by
+// the regular inflection rules of English.
+func Lemma(word string) string {
+	if lemma, ok := irregularForms[word]; ok {
+		return lemma
+	}
+	if lemmas[word] || len(word) < 4 || !isLetters(word) {
+		return word
+	}
+
+	switch {
+	case strings.HasSuffix(word, "ies"):
+		if len(word) == 4 {
+			// lies, ties
+			return word[:3]
+		}
+		return word[:len(word)-3] + "y"
+	case strings.HasSuffix(word, "ied"):
+		if len(word) == 4 {
+			// died, lied
+			return word[:3]
+		}
+		return word[:len(word)-3] + "y"
+	case strings.HasSuffix(word, "ss"), strings.HasSuffix(word, "us"),
+		strings.HasSuffix(word, "is"), strings.HasSuffix(word, "'s"):
+		return word
+	case strings.HasSuffix(word, "sses"), strings.HasSuffix(word, "zzes"),
+		strings.HasSuffix(word, "xes"), strings.HasSuffix(word, "ches"),
+		strings.HasSuffix(word, "shes"), strings.HasSuffix(word, "oes"):
+		return word[:len(word)-2]
+	case strings.HasSuffix(word, "s"):
+		return word[:len(word)-1]
+	case strings.HasSuffix(word, "eed"):
+		// agreed, guaranteed
+		return word[:len(word)-1]
+	case strings.HasSuffix(word, "ing"):
+		return restoreStem(word, word[:len(word)-3])
+	case strings.HasSuffix(word, "ed"):
+		return restoreStem(word, word[:len(word)-2])
+	}
+	return word
+}
+
+// restoreStem turns the stem left after removing -ing or -ed back into a word:
+// it undoes consonant doubling ("runn" → "run") and restores a silent e ("mak" → "make").
+func restoreStem(word, stem string) string {
+	// thing, bring, shed: 語幹に母音がなければ活用形ではない
+	if !strings.ContainsAny(stem, "aeiouy") {
+		return word
+	}
+	n := len(stem)
+	last := stem[n-1]
+
+	switch {
+	case n <= 2:
+		// using, used
+		return stem + "e"
+	case isVowel(last):
+		// seeing, argued
+		if last == 'u' {
+			return stem + "e"
+		}
+		return stem
+	case n >= 4 && stem[n-2] == last && !strings.ContainsRune("lsfz", rune(last)) &&
+		isVowel(stem[n-3]) && !isVowel(stem[n-4]):
+		// running, stopped, beginning
+		return stem[:n-1]
+	case strings.HasSuffix(stem, "ell") && syllables(stem) >= 2:
+		// travelling, cancelled
+		return stem[:n-1]
+	case needsSilentE(stem):
+		return stem + "e"
+	}
+	return stem
+}
+
+// needsSilentE reports whether stem is a verb that ends in a silent e once restored.
+func needsSilentE(stem string) bool {
+	n := len(stem)
+	last := stem[n-1]
+
+	switch {
+	case last == 'c' || last == 'v':
+		// dancing, receiving
+		return true
+	case strings.HasSuffix(stem, "rg"), strings.HasSuffix(stem, "dg"):
+		// charging, judging
+		return true
+	case strings.HasSuffix(stem, "iz"), strings.HasSuffix(stem, "yz"):
+		// organizing, analyzed
+		return true
+	case (strings.HasSuffix(stem, "ir") && !strings.HasSuffix(stem, "air")) ||
+		(strings.HasSuffix(stem, "ur") && !strings.HasSuffix(stem, "our")):
+		// requiring, cured
+		return true
+	case n >= 3 && (last == 's' || last == 'z') && isVowel(stem[n-2]) && isVowel(stem[n-3]):
+		// causing, pleased, freezing
+		return true
+	case n >= 5 && strings.HasSuffix(stem, "at") && !isVowel(stem[n-3]):
+		// communicating, separated
+		return true
+	case strings.HasSuffix(stem, "iat"), strings.HasSuffix(stem, "uat"), strings.HasSuffix(stem, "creat"):
+		// appreciating, evaluated, created: -eat は treat, repeat のように e を付けない語が多いため create の系統だけ
+		return true
+	}
+
+	if silentEStems[stem] {
+		return true
+	}
+
+	// 子音・母音・子音で終わる語（making, hoped）
+	if last == 'w' || last == 'x' || last == 'y' || isVowel(last) || !isVowel(stem[n-2]) {
+		return false
+	}
+	if n >= 3 && isVowel(stem[n-3]) {
+		return false
+	}
+	if syllables(stem) == 1 {
+		return true
+	}
+	// 2音節以上は語尾で判断する（deciding, included, combined）。visiting, opening は e を付けない
+	for _, suffix := range silentESuffixes {
+		if strings.HasSuffix(stem, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// syllables counts the groups of vowels in word.
+func syllables(word string) int {
+	count := 0
+	for i := 0; i < len(word); i++ {
+		if isVowel(word[i]) && (i == 0 || !isVowel(word[i-1])) {
+			count++
+		}
+	}
+	return count
+}
+
+func isVowel(c byte) bool {
+	return strings.IndexByte("aeiou", c) >= 0
+}
+
+func isLetters(word string) bool {
+	for i := 0; i < len(word); i++ {
+		if word[i] < 'a' || word[i] > 'z' {
+			// "'s" はそのまま残す
+			if word[i] != '\'' {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,80 @@
+package normalize
+
+import "testing"
+
+func TestLemma(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		// 不規則変化
+		{"thought", "think"},
+		{"was", "be"},
+		{"shot", "shoot"},
+		{"ran", "run"},
+		{"written", "write"},
+		{"children", "child"},
+		{"knives", "knife"},
+		{"analyses", "analysis"},
+		// -s, -es, -ies
+		{"cats", "cat"},
+		{"boxes", "box"},
+		{"watches", "watch"},
+		{"potatoes", "potato"},
+		{"studies", "study"},
+		{"lies", "lie"},
+		{"movies", "movie"},
+		// 子音の重複
+		{"running", "run"},
+		{"stopped", "stop"},
+		{"beginning", "begin"},
+		{"travelling", "travel"},
+		// 語末の e
+		{"making", "make"},
+		{"hoped", "hope"},
+		{"dancing", "dance"},
+		{"judging", "judge"},
+		{"organizing", "organize"},
+		{"causing", "cause"},
+		{"communicated", "communicate"},
+		{"created", "create"},
+		{"creating", "create"},
+		{"recreated", "recreate"},
+		{"appreciated", "appreciate"},
+		{"evaluating", "evaluate"},
+		{"graduated", "graduate"},
+		{"changed", "change"},
+		{"ignored", "ignore"},
+		{"deciding", "decide"},
+		{"included", "include"},
+		{"combined", "combine"},
+		// e を付けない語
+		{"treated", "treat"},
+		{"repeated", "repeat"},
+		{"visited", "visit"},
+		{"opening", "open"},
+		{"colored", "color"},
+		{"seeing", "see"},
+		{"agreed", "agree"},
+		// 活用形に見えるが原形の語
+		{"news", "news"},
+		{"morning", "morning"},
+		{"lens", "lens"},
+		{"tired", "tired"},
+		{"thing", "thing"},
+		{"bus", "bus"},
+		{"need", "need"},
+		// 短い語・記号を含む語はそのまま
+		{"its", "its"},
+		{"it's", "it's"},
+		{"e-mails", "e-mails"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			if got := Lemma(tt.word); got != tt.want {
+				t.Errorf("Lemma(%q) = %q, want %q", tt.word, got, tt.want)
+			}
+		})
+	}
+}
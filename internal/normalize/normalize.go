@@ -0,0 +1,41 @@
+// Package normalize turns the words sent by clients into the headwords stored in the word list,
+// so that "Running", "running" and "ran" are recorded as the same word.
+package normalize
+
+import (
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Word returns the headword of word in lang, an ISO 639-1 code such as "en".
+// The word is NFC normalised, trimmed and case folded, and English words and the
+// verbs of English phrasal verbs are reduced to their dictionary form by Lemma.
+func Word(word, lang string) string {
+	word = Fold(word, lang)
+
+	if lang != "en" {
+		return word
+	}
+	if strings.Contains(word, " ") {
+		return lemmatizePhrase(word)
+	}
+	return Lemma(word)
+}
+
+// Fold NFC normalises, trims and case folds word in lang without reducing it to its dictionary form,
+// for input that is still being typed.
+// English is fully case folded; other languages are lower cased by their own rules
+// so that, for example, the German "ß" is not turned into "ss".
+func Fold(word, lang string) string {
+	word = norm.NFC.String(word)
+	// 連続する空白は1つにまとめる
+	word = strings.Join(strings.Fields(word), " ")
+	// Caser は並行に使えないため呼び出しごとに作る
+	if lang == "en" {
+		return cases.Fold().String(word)
+	}
+	return cases.Lower(language.Make(lang)).String(word)
+}
@@ -0,0 +1,27 @@
+package normalize
+
+import "testing"
+
+func TestWord(t *testing.T) {
+	tests := []struct {
+		word string
+		lang string
+		want string
+	}{
+		{"  Running ", "en", "run"},
+		{"GAVE   UP", "en", "give up"},
+		{"Straße", "de", "straße"},
+		{"STRASSE", "de", "strasse"},
+		{"Straße", "en", "strasse"},
+		{"ｶﾀｶﾅ", "ja", "ｶﾀｶﾅ"},
+		{"Ran", "fr", "ran"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.lang+"/"+tt.word, func(t *testing.T) {
+			if got := Word(tt.word, tt.lang); got != tt.want {
+				t.Errorf("Word(%q, %q) = %q, want %q", tt.word, tt.lang, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package normalize
+
+import "strings"
+
+// Kind classifies a headword as a single word or a multi-word expression.
+type Kind string
+
+const (
+	KindWord        Kind = "word"         // run
+	KindPhrasalVerb Kind = "phrasal_verb" // give up, look forward to
+	KindPhrase      Kind = "phrase"       // in spite of
+)
+
+// MaxPhraseWords is the longest expression, in words, accepted as a headword.
+const MaxPhraseWords = 6
+
+// particles are the adverbs and prepositions that follow the verb of an English phrasal verb.
+var particles = map[string]bool{
+	"about": true, "across": true, "after": true, "ahead": true, "along": true,
+	"apart": true, "around": true, "aside": true, "away": true, "back": true,
+	"by": true, "down": true, "for": true, "forth": true, "forward": true,
+	"in": true, "into": true, "off": true, "on": true, "out": true,
+	"over": true, "round": true, "through": true, "together": true, "up": true,
+	"upon": true, "with": true,
+}
+
+// nonVerbs are words that start phrases but are never the verb of a phrasal verb.
+var nonVerbs = map[string]bool{
+	"a": true, "an": true, "the": true, "in": true, "on": true, "at": true,
+	"by": true, "for": true, "of": true, "to": true, "as": true, "so": true,
+	"and": true, "or": true, "but": true, "all": true, "no": true, "not": true,
+}
+
+// KindOf classifies a normalised headword in language, an ISO 639-1 code such as "en".
+func KindOf(word, language string) Kind {
+	fields := strings.Fields(word)
+	switch {
+	case len(fields) <= 1:
+		return KindWord
+	case language == "en" && isPhrasalVerb(fields):
+		return KindPhrasalVerb
+	default:
+		return KindPhrase
+	}
+}
+
+// Head returns the word of a phrase that a dictionary is most likely to list it under:
+// the verb of a phrasal verb, otherwise the longest word ("in spite of" → "spite").
+func Head(phrase, language string) string {
+	fields := strings.Fields(phrase)
+	if len(fields) == 0 {
+		return phrase
+	}
+	if language == "en" && isPhrasalVerb(fields) {
+		return fields[0]
+	}
+
+	head := fields[0]
+	for _, field := range fields[1:] {
+		if len(field) > len(head) {
+			head = field
+		}
+	}
+	return head
+}
+
+// isPhrasalVerb reports whether fields is a verb followed by one or two particles,
+// optionally ending in a preposition ("look forward to", "put up with").
+func isPhrasalVerb(fields []string) bool {
+	if len(fields) < 2 || len(fields) > 4 || nonVerbs[fields[0]] || !particles[fields[1]] {
+		return false
+	}
+	for _, field := range fields[2:] {
+		if !particles[field] && field != "to" && field != "at" && field != "of" {
+			return false
+		}
+	}
+	return true
+}
+
+// lemmatizePhrase reduces the verb of an English phrasal verb to its dictionary form
+// ("gave up" → "give up"); other phrases are returned unchanged.
+func lemmatizePhrase(phrase string) string {
+	fields := strings.Fields(phrase)
+	if !isPhrasalVerb(fields) {
+		return phrase
+	}
+	fields[0] = Lemma(fields[0])
+	return strings.Join(fields, " ")
+}
@@ -0,0 +1,122 @@
+package pronounce
+
+import "strings"
+
+// vowels maps ARPAbet vowels onto IPA (General American).
+var vowels = map[string]string{
+	"AA": "ɑ", "AE": "æ", "AH": "ʌ", "AO": "ɔ", "AW": "aʊ",
+	"AY": "aɪ", "EH": "ɛ", "ER": "ɝ", "EY": "eɪ", "IH": "ɪ",
+	"IY": "i", "OW": "oʊ", "OY": "ɔɪ", "UH": "ʊ", "UW": "u",
+}
+
+// reducedVowels are the IPA of unstressed vowels that are written differently.
+var reducedVowels = map[string]string{
+	"AH": "ə",
+	"ER": "ɚ",
+}
+
+// consonants maps ARPAbet consonants onto IPA.
+var consonants = map[string]string{
+	"B": "b", "CH": "tʃ", "D": "d", "DH": "ð", "F": "f", "G": "ɡ",
+	"HH": "h", "JH": "dʒ", "K": "k", "L": "l", "M": "m", "N": "n",
+	"NG": "ŋ", "P": "p", "R": "ɹ", "S": "s", "SH": "ʃ", "T": "t",
+	"TH": "θ", "V": "v", "W": "w", "Y": "j", "Z": "z", "ZH": "ʒ",
+}
+
+// onsets are the consonant clusters that can begin an English syllable.
+// 強勢記号は音節の頭に置くため、母音の前の子音のどこから音節が始まるかを決めるのに使う
+var onsets = map[string]bool{
+	"P L": true, "P R": true, "P Y": true, "B L": true, "B R": true, "B Y": true,
+	"T R": true, "T W": true, "D R": true, "D W": true, "K L": true, "K R": true,
+	"K W": true, "K Y": true, "G L": true, "G R": true, "G W": true, "F L": true,
+	"F R": true, "F Y": true, "TH R": true, "TH W": true, "SH R": true, "V Y": true,
+	"M Y": true, "HH Y": true, "S L": true, "S W": true, "S P": true, "S T": true,
+	"S K": true, "S M": true, "S N": true, "S F": true,
+	"S P L": true, "S P R": true, "S P Y": true, "S T R": true, "S K R": true,
+	"S K W": true, "S K Y": true,
+}
+
+// phone is an ARPAbet phone split into its symbol and stress (-1 for consonants).
+type phone struct {
+	symbol string
+	stress int
+}
+
+// IPA converts an ARPAbet transcription such as "IH2 M P AO1 R T AH0 N T" into IPA
+// ("ˌɪmˈpɔɹtənt"). Stress is marked only on words of more than one syllable.
+func IPA(arpabet string) string {
+	var phones []phone
+	syllables := 0
+	for _, symbol := range strings.Fields(arpabet) {
+		p := phone{symbol: symbol, stress: -1}
+		if last := symbol[len(symbol)-1]; last >= '0' && last <= '2' {
+			p = phone{symbol: symbol[:len(symbol)-1], stress: int(last - '0')}
+			syllables++
+		}
+		phones = append(phones, p)
+	}
+
+	var b strings.Builder
+	start := 0 // 直前の母音の次の位置
+	for i, p := range phones {
+		if p.stress < 0 {
+			continue
+		}
+		onset := start
+		if start > 0 {
+			onset = onsetStart(phones[start:i]) + start
+		}
+		for _, c := range phones[start:onset] {
+			b.WriteString(consonants[c.symbol])
+		}
+		if syllables > 1 {
+			switch p.stress {
+			case 1:
+				b.WriteString("ˈ")
+			case 2:
+				b.WriteString("ˌ")
+			}
+		}
+		for _, c := range phones[onset:i] {
+			b.WriteString(consonants[c.symbol])
+		}
+		b.WriteString(vowel(p))
+		start = i + 1
+	}
+	for _, c := range phones[start:] {
+		b.WriteString(consonants[c.symbol])
+	}
+
+	return b.String()
+}
+
+// onsetStart returns the index in cluster, the consonants between two vowels, where the
+// syllable of the second vowel begins: the longest valid onset at the end of the cluster.
+func onsetStart(cluster []phone) int {
+	for i := range cluster {
+		if isOnset(cluster[i:]) {
+			return i
+		}
+	}
+	return len(cluster)
+}
+
+// isOnset reports whether the consonants can begin a syllable.
+func isOnset(cluster []phone) bool {
+	if len(cluster) == 1 {
+		return cluster[0].symbol != "NG"
+	}
+	symbols := make([]string, len(cluster))
+	for i, c := range cluster {
+		symbols[i] = c.symbol
+	}
+	return onsets[strings.Join(symbols, " ")]
+}
+
+// vowel returns the IPA of a vowel, using the reduced form when it is unstressed.
+func vowel(p phone) string {
+	if reduced, ok := reducedVowels[p.symbol]; ok && p.stress == 0 {
+		return reduced
+	}
+	return vowels[p.symbol]
+}
@@ -0,0 +1,120 @@
+// Package pronounce looks up the pronunciation of English words in a CMUdict formatted
+// pronouncing dictionary and renders it in IPA with stress marks.
+package pronounce
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// cmudictFile is a small starter set of common words in CMUdict format.
+// 全語彙は PRONUNCIATION_FILE に CMUdict を指定して読み込む
+//
+//go:embed cmudict.txt
+var cmudictFile string
+
+// Pronunciation is the pronunciation of a word.
+type Pronunciation struct {
+	// ARPAbet is the CMUdict transcription, e.g. "HH AE1 P IY0"
+	ARPAbet string `json:"arpabet"`
+	// IPA is the transcription in the International Phonetic Alphabet, e.g. "ˈhæpi"
+	IPA string `json:"ipa"`
+}
+
+// Dictionary maps lower case words onto their ARPAbet transcription.
+type Dictionary map[string]string
+
+// Bundled returns the pronunciations bundled with the server.
+func Bundled() Dictionary {
+	dict, err := Load(strings.NewReader(cmudictFile))
+	if err != nil {
+		panic(fmt.Sprintf("invalid bundled pronunciation dictionary: %v", err))
+	}
+	return dict
+}
+
+// FromEnv returns the bundled pronunciations, extended with the CMUdict file at
+// PRONUNCIATION_FILE when it is set.
+func FromEnv() (Dictionary, error) {
+	dict := Bundled()
+
+	path := os.Getenv("PRONUNCIATION_FILE")
+	if path == "" {
+		return dict, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open pronunciation file: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	loaded, err := Load(f)
+	if err != nil {
+		return nil, err
+	}
+	for word, arpabet := range loaded {
+		dict[word] = arpabet
+	}
+	return dict, nil
+}
+
+// Load reads a CMUdict formatted file: "WORD  P R OW0 N" per line in cmudict-0.7b,
+// or "word p r ow0 n" in cmudict.dict. Alternative pronunciations ("READ(2)") and
+// comments (";;;" lines and "#" suffixes) are skipped, keeping the first pronunciation.
+func Load(r io.Reader) (Dictionary, error) {
+	dict := Dictionary{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, ";;;") {
+			continue
+		}
+		line, _, _ = strings.Cut(line, "#")
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		word := strings.ToLower(fields[0])
+		if strings.HasSuffix(word, ")") {
+			continue
+		}
+		if _, found := dict[word]; found {
+			continue
+		}
+		dict[word] = strings.ToUpper(strings.Join(fields[1:], " "))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read pronunciation file: %w", err)
+	}
+
+	return dict, nil
+}
+
+// Lookup returns the pronunciation of a lower case word or phrase.
+// フレーズはすべての語の発音がある場合のみ返し、ARPAbet の語の区切りは " | " で表す
+func (d Dictionary) Lookup(word string) (Pronunciation, bool) {
+	var arpabets, ipas []string
+	for _, field := range strings.Fields(word) {
+		arpabet, ok := d[field]
+		if !ok {
+			return Pronunciation{}, false
+		}
+		arpabets = append(arpabets, arpabet)
+		ipas = append(ipas, IPA(arpabet))
+	}
+	if len(arpabets) == 0 {
+		return Pronunciation{}, false
+	}
+
+	return Pronunciation{
+		ARPAbet: strings.Join(arpabets, " | "),
+		IPA:     strings.Join(ipas, " "),
+	}, true
+}
@@ -0,0 +1,123 @@
+package scheduler
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// FSRSWeights holds the 17 parameters of the FSRS-4.5 memory model.
+type FSRSWeights [17]float64
+
+// DefaultFSRSWeights are the published FSRS-4.5 defaults, used until a user's
+// own parameters have been fitted.
+var DefaultFSRSWeights = FSRSWeights{
+	0.4872, 1.4003, 3.7145, 13.8206, 5.1618, 1.2298, 0.8975, 0.031,
+	1.6474, 0.1367, 1.0461, 2.1072, 0.0793, 0.3246, 1.587, 0.2272, 2.8755,
+}
+
+// FSRSWeightsFromSlice converts stored weights into FSRSWeights.
+func FSRSWeightsFromSlice(values []float64) (FSRSWeights, error) {
+	var w FSRSWeights
+	if len(values) != len(w) {
+		return w, fmt.Errorf("expected %d FSRS weights, got %d", len(w), len(values))
+	}
+	copy(w[:], values)
+	return w, nil
+}
+
+const (
+	fsrsDecay  = -0.5
+	fsrsFactor = 19.0 / 81.0
+	// 目標とする想起率
+	fsrsRequestRetention = 0.9
+	fsrsMaxIntervalDays  = 36500
+)
+
+// FSRS implements the Free Spaced Repetition Scheduler (FSRS-4.5).
+type FSRS struct {
+	Weights FSRSWeights
+}
+
+func NewFSRS(weights FSRSWeights) FSRS {
+	return FSRS{Weights: weights}
+}
+
+func (FSRS) Name() string {
+	return "fsrs"
+}
+
+func (f FSRS) Schedule(state State, grade Grade, now time.Time) State {
+	var stability, difficulty float64
+
+	if state.Stability <= 0 || state.LastReviewedAt.IsZero() {
+		// 初回の復習（または他のスケジューラから切り替えた直後）
+		stability = f.initStability(grade)
+		difficulty = f.initDifficulty(grade)
+	} else {
+		elapsed := now.Sub(state.LastReviewedAt).Hours() / 24
+		stability, difficulty = f.next(state.Stability, state.Difficulty, elapsed, grade)
+	}
+
+	interval := f.interval(stability)
+	next := State{
+		EaseFactor:   state.EaseFactor,
+		IntervalDays: interval,
+		Repetitions:  state.Repetitions + 1,
+		DueAt:        addDays(now, interval),
+		Stability:    stability,
+		Difficulty:   difficulty,
+	}
+	if grade == GradeAgain {
+		next.Repetitions = 0
+	}
+	return next
+}
+
+// retrievability is the predicted probability of recall after elapsed days.
+func retrievability(elapsedDays, stability float64) float64 {
+	return math.Pow(1+fsrsFactor*elapsedDays/stability, fsrsDecay)
+}
+
+func (f FSRS) initStability(grade Grade) float64 {
+	return math.Max(f.Weights[grade-1], 0.1)
+}
+
+func (f FSRS) initDifficulty(grade Grade) float64 {
+	return clampDifficulty(f.Weights[4] - float64(grade-3)*f.Weights[5])
+}
+
+// next returns the stability and difficulty after a review made elapsedDays after the previous one.
+func (f FSRS) next(stability, difficulty, elapsedDays float64, grade Grade) (float64, float64) {
+	w := f.Weights
+	r := retrievability(elapsedDays, stability)
+
+	nextDifficulty := difficulty - w[6]*float64(grade-3)
+	// 初期難易度への平均回帰
+	nextDifficulty = clampDifficulty(w[7]*f.initDifficulty(GradeGood) + (1-w[7])*nextDifficulty)
+
+	var nextStability float64
+	if grade == GradeAgain {
+		nextStability = w[11] * math.Pow(difficulty, -w[12]) * (math.Pow(stability+1, w[13]) - 1) * math.Exp(w[14]*(1-r))
+	} else {
+		hardPenalty, easyBonus := 1.0, 1.0
+		if grade == GradeHard {
+			hardPenalty = w[15]
+		}
+		if grade == GradeEasy {
+			easyBonus = w[16]
+		}
+		nextStability = stability * (math.Exp(w[8])*(11-difficulty)*math.Pow(stability, -w[9])*(math.Exp(w[10]*(1-r))-1)*hardPenalty*easyBonus + 1)
+	}
+
+	return math.Max(nextStability, 0.1), nextDifficulty
+}
+
+func (FSRS) interval(stability float64) int {
+	days := stability / fsrsFactor * (math.Pow(fsrsRequestRetention, 1/fsrsDecay) - 1)
+	return int(math.Min(math.Max(math.Round(days), 1), fsrsMaxIntervalDays))
+}
+
+func clampDifficulty(d float64) float64 {
+	return math.Min(math.Max(d, 1), 10)
+}
@@ -0,0 +1,122 @@
+package scheduler
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// ReviewLog is a single past review used to fit FSRS weights.
+type ReviewLog struct {
+	Grade      Grade
+	ReviewedAt time.Time
+}
+
+// FSRSFit is the result of fitting FSRS weights to a review history.
+type FSRSFit struct {
+	Weights FSRSWeights
+	// Loss is the mean log loss of the recall predictions
+	Loss float64
+	// Samples is the number of reviews the loss was computed from
+	Samples int
+}
+
+// MinFSRSSamples is the number of predictable reviews required before fitting.
+// 復習回数が少ないと既定値より悪い値になりやすい
+const MinFSRSSamples = 100
+
+// 各パラメータの探索範囲
+var fsrsWeightBounds = [17][2]float64{
+	{0.1, 100}, {0.1, 100}, {0.1, 100}, {0.1, 100},
+	{1, 10}, {0.1, 5}, {0.1, 5}, {0, 0.5},
+	{0, 3}, {0.1, 0.8}, {0.01, 2.5}, {0.5, 5},
+	{0.01, 0.2}, {0.01, 0.9}, {0.01, 2}, {0, 1}, {1, 4},
+}
+
+// FitFSRS fits FSRS weights to histories, where each history is one word's
+// reviews in chronological order. It starts from initial and runs Adam on
+// numerically estimated gradients for the given number of iterations.
+func FitFSRS(histories [][]ReviewLog, initial FSRSWeights, iterations int) (FSRSFit, error) {
+	loss, samples := fsrsLoss(histories, initial)
+	if samples < MinFSRSSamples {
+		return FSRSFit{}, fmt.Errorf("not enough reviews to fit FSRS weights: %d < %d", samples, MinFSRSSamples)
+	}
+
+	const (
+		learningRate = 0.04
+		beta1        = 0.9
+		beta2        = 0.999
+		epsilon      = 1e-8
+		step         = 1e-4
+	)
+
+	best := FSRSFit{Weights: initial, Loss: loss, Samples: samples}
+	w := initial
+	var m, v [17]float64
+
+	for t := 1; t <= iterations; t++ {
+		// 中心差分で勾配を求める
+		var grad [17]float64
+		for i := range w {
+			plus, minus := w, w
+			plus[i] += step
+			minus[i] -= step
+			lossPlus, _ := fsrsLoss(histories, plus)
+			lossMinus, _ := fsrsLoss(histories, minus)
+			grad[i] = (lossPlus - lossMinus) / (2 * step)
+		}
+
+		for i := range w {
+			m[i] = beta1*m[i] + (1-beta1)*grad[i]
+			v[i] = beta2*v[i] + (1-beta2)*grad[i]*grad[i]
+			mHat := m[i] / (1 - math.Pow(beta1, float64(t)))
+			vHat := v[i] / (1 - math.Pow(beta2, float64(t)))
+			w[i] -= learningRate * mHat / (math.Sqrt(vHat) + epsilon)
+			w[i] = math.Min(math.Max(w[i], fsrsWeightBounds[i][0]), fsrsWeightBounds[i][1])
+		}
+
+		if loss, _ := fsrsLoss(histories, w); loss < best.Loss {
+			best.Weights = w
+			best.Loss = loss
+		}
+	}
+
+	return best, nil
+}
+
+// fsrsLoss replays every history with weights and returns the mean log loss
+// of the recall predictions together with the number of predicted reviews.
+func fsrsLoss(histories [][]ReviewLog, weights FSRSWeights) (float64, int) {
+	f := NewFSRS(weights)
+	total := 0.0
+	samples := 0
+
+	for _, history := range histories {
+		if len(history) < 2 {
+			continue
+		}
+
+		stability := f.initStability(history[0].Grade)
+		difficulty := f.initDifficulty(history[0].Grade)
+
+		for i := 1; i < len(history); i++ {
+			elapsed := history[i].ReviewedAt.Sub(history[i-1].ReviewedAt).Hours() / 24
+			p := retrievability(elapsed, stability)
+			p = math.Min(math.Max(p, 1e-6), 1-1e-6)
+
+			if history[i].Grade == GradeAgain {
+				total -= math.Log(1 - p)
+			} else {
+				total -= math.Log(p)
+			}
+			samples++
+
+			stability, difficulty = f.next(stability, difficulty, elapsed, history[i].Grade)
+		}
+	}
+
+	if samples == 0 {
+		return 0, 0
+	}
+	return total / float64(samples), samples
+}
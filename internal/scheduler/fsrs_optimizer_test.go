@@ -0,0 +1,80 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+// reviewHistories builds the histories of words that are reviewed again after 1, 3 and 7 days.
+// Every forgetEvery-th word is forgotten at its second review.
+func reviewHistories(words, forgetEvery int) [][]ReviewLog {
+	start := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	histories := make([][]ReviewLog, words)
+	for i := range histories {
+		at := start
+		histories[i] = []ReviewLog{{Grade: GradeGood, ReviewedAt: at}}
+		for j, days := range []int{1, 3, 7} {
+			at = addDays(at, days)
+			grade := GradeGood
+			if j == 0 && forgetEvery > 0 && i%forgetEvery == 0 {
+				grade = GradeAgain
+			}
+			histories[i] = append(histories[i], ReviewLog{Grade: grade, ReviewedAt: at})
+		}
+	}
+	return histories
+}
+
+func TestFitFSRS(t *testing.T) {
+	tests := []struct {
+		name        string
+		histories   [][]ReviewLog
+		wantSamples int
+		wantErr     bool
+	}{
+		{
+			name:        "enough reviews",
+			histories:   reviewHistories(50, 5),
+			wantSamples: 150,
+		},
+		{
+			name:      "too few reviews",
+			histories: reviewHistories(20, 5),
+			wantErr:   true,
+		},
+		{
+			name:      "single reviews are not predictable",
+			histories: [][]ReviewLog{{{Grade: GradeGood}}, {{Grade: GradeEasy}}},
+			wantErr:   true,
+		},
+		{
+			name:    "no reviews",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fit, err := FitFSRS(tt.histories, DefaultFSRSWeights, 10)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if fit.Samples != tt.wantSamples {
+				t.Errorf("samples = %d, want %d", fit.Samples, tt.wantSamples)
+			}
+			initialLoss, _ := fsrsLoss(tt.histories, DefaultFSRSWeights)
+			if fit.Loss > initialLoss {
+				t.Errorf("loss = %v, worse than the initial %v", fit.Loss, initialLoss)
+			}
+			for i, w := range fit.Weights {
+				if w < fsrsWeightBounds[i][0] || w > fsrsWeightBounds[i][1] {
+					t.Errorf("weight %d = %v, outside %v", i, w, fsrsWeightBounds[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package scheduler
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestFSRSFirstReview(t *testing.T) {
+	tests := []struct {
+		grade          Grade
+		wantStability  float64
+		wantDifficulty float64
+		wantInterval   int
+		wantReps       int
+	}{
+		{GradeAgain, 0.4872, 7.6214, 1, 0},
+		{GradeHard, 1.4003, 6.3916, 1, 1},
+		{GradeGood, 3.7145, 5.1618, 4, 1},
+		{GradeEasy, 13.8206, 3.932, 14, 1},
+	}
+
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	fsrs := NewFSRS(DefaultFSRSWeights)
+	for _, tt := range tests {
+		t.Run(tt.grade.String(), func(t *testing.T) {
+			state := fsrs.Schedule(State{}, tt.grade, now)
+
+			if math.Abs(state.Stability-tt.wantStability) > 1e-9 {
+				t.Errorf("stability = %v, want %v", state.Stability, tt.wantStability)
+			}
+			if math.Abs(state.Difficulty-tt.wantDifficulty) > 1e-9 {
+				t.Errorf("difficulty = %v, want %v", state.Difficulty, tt.wantDifficulty)
+			}
+			if state.IntervalDays != tt.wantInterval {
+				t.Errorf("interval = %d, want %d", state.IntervalDays, tt.wantInterval)
+			}
+			if state.Repetitions != tt.wantReps {
+				t.Errorf("repetitions = %d, want %d", state.Repetitions, tt.wantReps)
+			}
+		})
+	}
+}
+
+func TestFSRSSecondReview(t *testing.T) {
+	// 初回に good と答え、期限どおりに2回目の復習をした場合
+	tests := []struct {
+		grade          Grade
+		wantStability  float64
+		wantDifficulty float64
+		wantInterval   int
+	}{
+		{GradeAgain, 1.4332, 6.9012, 1},
+		{GradeHard, 6.2350, 6.0315, 6},
+		{GradeGood, 14.8081, 5.1618, 15},
+		{GradeEasy, 35.6141, 4.2921, 36},
+	}
+
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	fsrs := NewFSRS(DefaultFSRSWeights)
+	first := fsrs.Schedule(State{}, GradeGood, now)
+	first.LastReviewedAt = now
+
+	for _, tt := range tests {
+		t.Run(tt.grade.String(), func(t *testing.T) {
+			state := fsrs.Schedule(first, tt.grade, first.DueAt)
+
+			if math.Abs(state.Stability-tt.wantStability) > 1e-4 {
+				t.Errorf("stability = %v, want %v", state.Stability, tt.wantStability)
+			}
+			if math.Abs(state.Difficulty-tt.wantDifficulty) > 1e-4 {
+				t.Errorf("difficulty = %v, want %v", state.Difficulty, tt.wantDifficulty)
+			}
+			if state.IntervalDays != tt.wantInterval {
+				t.Errorf("interval = %d, want %d", state.IntervalDays, tt.wantInterval)
+			}
+			// 思い出せなかった場合だけ安定度が下がる
+			if forgot := state.Stability < first.Stability; forgot != (tt.grade == GradeAgain) {
+				t.Errorf("stability went from %v to %v", first.Stability, state.Stability)
+			}
+		})
+	}
+}
+
+func TestFSRSWeightsFromSlice(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []float64
+		wantErr bool
+	}{
+		{"defaults", DefaultFSRSWeights[:], false},
+		{"too few", DefaultFSRSWeights[:16], true},
+		{"too many", append(DefaultFSRSWeights[:], 1), true},
+		{"empty", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			weights, err := FSRSWeightsFromSlice(tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && weights != DefaultFSRSWeights {
+				t.Errorf("weights = %v, want %v", weights, DefaultFSRSWeights)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package scheduler
+
+import "time"
+
+// Leitner implements the Leitner box system.
+// 正解するたびに次の箱へ進み、間違えると最初の箱に戻る
+type Leitner struct {
+	// BoxIntervals holds the review interval in days for each box.
+	BoxIntervals []int
+}
+
+func NewLeitner() Leitner {
+	return Leitner{BoxIntervals: []int{1, 2, 4, 8, 16, 32}}
+}
+
+func (Leitner) Name() string {
+	return "leitner"
+}
+
+func (l Leitner) Schedule(state State, grade Grade, now time.Time) State {
+	// Repetitions は現在の箱の番号（0始まり）として扱う
+	box := state.Repetitions
+	switch grade {
+	case GradeAgain:
+		box = 0
+	case GradeHard:
+		// 難しかった場合は同じ箱に留まる
+	default:
+		box++
+	}
+	if box >= len(l.BoxIntervals) {
+		box = len(l.BoxIntervals) - 1
+	}
+
+	ease := state.EaseFactor
+	if ease == 0 {
+		ease = DefaultEaseFactor
+	}
+
+	interval := l.BoxIntervals[box]
+	return State{
+		EaseFactor:   ease,
+		IntervalDays: interval,
+		Repetitions:  box,
+		DueAt:        addDays(now, interval),
+	}
+}
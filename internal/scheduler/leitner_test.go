@@ -0,0 +1,84 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLeitnerSchedule(t *testing.T) {
+	type step struct {
+		grade        Grade
+		wantBox      int
+		wantInterval int
+	}
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "correct answers move up a box",
+			steps: []step{
+				{GradeGood, 1, 2},
+				{GradeEasy, 2, 4},
+				{GradeGood, 3, 8},
+				{GradeGood, 4, 16},
+				{GradeGood, 5, 32},
+			},
+		},
+		{
+			name: "the last box is kept",
+			steps: []step{
+				{GradeGood, 1, 2},
+				{GradeGood, 2, 4},
+				{GradeGood, 3, 8},
+				{GradeGood, 4, 16},
+				{GradeGood, 5, 32},
+				{GradeGood, 5, 32},
+			},
+		},
+		{
+			name: "hard stays in the same box",
+			steps: []step{
+				{GradeGood, 1, 2},
+				{GradeGood, 2, 4},
+				{GradeHard, 2, 4},
+				{GradeGood, 3, 8},
+			},
+		},
+		{
+			name: "again goes back to the first box",
+			steps: []step{
+				{GradeGood, 1, 2},
+				{GradeGood, 2, 4},
+				{GradeGood, 3, 8},
+				{GradeAgain, 0, 1},
+				{GradeHard, 0, 1},
+				{GradeGood, 1, 2},
+			},
+		},
+	}
+
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			leitner := NewLeitner()
+			var state State
+			reviewedAt := now
+			for i, step := range tt.steps {
+				state = leitner.Schedule(state, step.grade, reviewedAt)
+
+				if state.Repetitions != step.wantBox {
+					t.Errorf("step %d (%s): box = %d, want %d", i, step.grade, state.Repetitions, step.wantBox)
+				}
+				if state.IntervalDays != step.wantInterval {
+					t.Errorf("step %d (%s): interval = %d, want %d", i, step.grade, state.IntervalDays, step.wantInterval)
+				}
+				if state.EaseFactor != DefaultEaseFactor {
+					t.Errorf("step %d (%s): ease = %v, want %v", i, step.grade, state.EaseFactor, DefaultEaseFactor)
+				}
+				reviewedAt = state.DueAt
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package scheduler
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// Grade は復習時の回答の評価を表す
+type Grade int
+
+const (
+	GradeAgain Grade = iota + 1 // 思い出せなかった
+	GradeHard                   // 思い出せたが難しかった
+	GradeGood                   // 問題なく思い出せた
+	GradeEasy                   // 簡単に思い出せた
+)
+
+var gradeNames = map[Grade]string{
+	GradeAgain: "again",
+	GradeHard:  "hard",
+	GradeGood:  "good",
+	GradeEasy:  "easy",
+}
+
+// Valid reports whether g is one of the defined grades.
+func (g Grade) Valid() bool {
+	return g >= GradeAgain && g <= GradeEasy
+}
+
+func (g Grade) String() string {
+	if name, ok := gradeNames[g]; ok {
+		return name
+	}
+	return fmt.Sprintf("Grade(%d)", int(g))
+}
+
+// ParseGrade converts a grade name such as "again" or "easy" into a Grade.
+func ParseGrade(name string) (Grade, error) {
+	for grade, n := range gradeNames {
+		if n == name {
+			return grade, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown grade: %s", name)
+}
+
+// State is the scheduling state stored on each word.
+type State struct {
+	EaseFactor   float64
+	IntervalDays int
+	Repetitions  int
+	DueAt        time.Time
+	// FSRS の記憶モデルで使用する値
+	Stability  float64
+	Difficulty float64
+	// LastReviewedAt is the time of the previous review, zero if never reviewed
+	LastReviewedAt time.Time
+}
+
+// Scheduler decides when a word should be reviewed next.
+type Scheduler interface {
+	// Name returns the identifier used in REVIEW_SCHEDULER.
+	Name() string
+	// Schedule returns the next state after a review graded with grade at now.
+	Schedule(state State, grade Grade, now time.Time) State
+}
+
+const DefaultEaseFactor = 2.5
+
+// New returns the scheduler registered under name.
+func New(name string) (Scheduler, error) {
+	switch name {
+	case "", "sm2":
+		return SM2{}, nil
+	case "leitner":
+		return NewLeitner(), nil
+	case "fsrs":
+		return NewFSRS(DefaultFSRSWeights), nil
+	default:
+		return nil, fmt.Errorf("unknown scheduler: %s", name)
+	}
+}
+
+// FromEnv returns the scheduler selected by the REVIEW_SCHEDULER environment variable.
+// 未設定の場合はSM-2を使用する
+func FromEnv() (Scheduler, error) {
+	return New(os.Getenv("REVIEW_SCHEDULER"))
+}
+
+// addDays returns now advanced by the given number of days.
+func addDays(now time.Time, days int) time.Time {
+	return now.Add(time.Duration(days) * 24 * time.Hour)
+}
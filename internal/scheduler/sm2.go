@@ -0,0 +1,64 @@
+package scheduler
+
+import (
+	"math"
+	"time"
+)
+
+const minEaseFactor = 1.3
+
+// SM2 implements the SuperMemo-2 algorithm.
+type SM2 struct{}
+
+func (SM2) Name() string {
+	return "sm2"
+}
+
+// quality maps a grade onto the 0-5 response quality scale used by SM-2.
+func (SM2) quality(grade Grade) float64 {
+	switch grade {
+	case GradeAgain:
+		return 1
+	case GradeHard:
+		return 3
+	case GradeEasy:
+		return 5
+	default:
+		return 4
+	}
+}
+
+func (s SM2) Schedule(state State, grade Grade, now time.Time) State {
+	q := s.quality(grade)
+
+	ease := state.EaseFactor
+	if ease == 0 {
+		ease = DefaultEaseFactor
+	}
+
+	next := State{EaseFactor: ease}
+
+	// 思い出せなかった場合は最初からやり直す
+	if q < 3 {
+		next.Repetitions = 0
+		next.IntervalDays = 1
+	} else {
+		switch state.Repetitions {
+		case 0:
+			next.IntervalDays = 1
+		case 1:
+			next.IntervalDays = 6
+		default:
+			next.IntervalDays = int(math.Round(float64(state.IntervalDays) * ease))
+		}
+		next.Repetitions = state.Repetitions + 1
+	}
+
+	next.EaseFactor = ease + (0.1 - (5-q)*(0.08+(5-q)*0.02))
+	if next.EaseFactor < minEaseFactor {
+		next.EaseFactor = minEaseFactor
+	}
+
+	next.DueAt = addDays(now, next.IntervalDays)
+	return next
+}
@@ -0,0 +1,91 @@
+package scheduler
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSM2Schedule(t *testing.T) {
+	type step struct {
+		grade        Grade
+		wantInterval int
+		wantReps     int
+		wantEase     float64
+	}
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "good keeps the ease and grows the interval",
+			steps: []step{
+				{GradeGood, 1, 1, 2.5},
+				{GradeGood, 6, 2, 2.5},
+				{GradeGood, 15, 3, 2.5},
+				{GradeGood, 38, 4, 2.5},
+			},
+		},
+		{
+			name: "easy raises the ease",
+			steps: []step{
+				{GradeEasy, 1, 1, 2.6},
+				{GradeEasy, 6, 2, 2.7},
+				{GradeEasy, 16, 3, 2.8},
+			},
+		},
+		{
+			name: "hard lowers the ease",
+			steps: []step{
+				{GradeHard, 1, 1, 2.36},
+				{GradeHard, 6, 2, 2.22},
+				{GradeHard, 13, 3, 2.08},
+			},
+		},
+		{
+			name: "again starts over",
+			steps: []step{
+				{GradeGood, 1, 1, 2.5},
+				{GradeGood, 6, 2, 2.5},
+				{GradeGood, 15, 3, 2.5},
+				{GradeAgain, 1, 0, 1.96},
+				{GradeGood, 1, 1, 1.96},
+			},
+		},
+		{
+			name: "ease does not fall below the minimum",
+			steps: []step{
+				{GradeAgain, 1, 0, 1.96},
+				{GradeAgain, 1, 0, 1.42},
+				{GradeAgain, 1, 0, 1.3},
+				{GradeAgain, 1, 0, 1.3},
+			},
+		},
+	}
+
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var state State
+			reviewedAt := now
+			for i, step := range tt.steps {
+				state = SM2{}.Schedule(state, step.grade, reviewedAt)
+
+				if state.IntervalDays != step.wantInterval {
+					t.Errorf("step %d (%s): interval = %d, want %d", i, step.grade, state.IntervalDays, step.wantInterval)
+				}
+				if state.Repetitions != step.wantReps {
+					t.Errorf("step %d (%s): repetitions = %d, want %d", i, step.grade, state.Repetitions, step.wantReps)
+				}
+				if math.Abs(state.EaseFactor-step.wantEase) > 1e-9 {
+					t.Errorf("step %d (%s): ease = %v, want %v", i, step.grade, state.EaseFactor, step.wantEase)
+				}
+				if want := addDays(reviewedAt, step.wantInterval); !state.DueAt.Equal(want) {
+					t.Errorf("step %d (%s): due = %v, want %v", i, step.grade, state.DueAt, want)
+				}
+				reviewedAt = state.DueAt
+			}
+		})
+	}
+}
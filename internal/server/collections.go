@@ -0,0 +1,450 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"tsumitan/internal/auth"
+	"tsumitan/internal/database"
+	"tsumitan/internal/dictionary"
+	"tsumitan/internal/normalize"
+
+	"github.com/labstack/echo/v4"
+)
+
+// collectionKind is a kind of user-defined group of words served under its own path.
+// デッキとタグは同じ形なので、ハンドラーも共通にする
+type collectionKind struct {
+	col database.Collection
+	// label is the name of the kind used in error messages
+	label string
+}
+
+var (
+	decks = collectionKind{col: database.Decks, label: "デッキ"}
+	tags  = collectionKind{col: database.Tags, label: "タグ"}
+)
+
+// デッキ・タグの名前と説明の最大文字数
+const (
+	maxCollectionNameLength        = 50
+	maxCollectionDescriptionLength = 500
+)
+
+type CollectionRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type CollectionResponse struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	WordCount   int    `json:"word_count"`
+	CreatedAt   string `json:"created_at"`
+	UpdatedAt   string `json:"updated_at"`
+}
+
+type CollectionWordResponse struct {
+	Word        string `json:"word"`
+	Lang        string `json:"lang"`
+	Kind        string `json:"kind"`
+	Level       string `json:"level,omitempty"`
+	SearchCount int    `json:"search_count"`
+	ReviewCount int    `json:"review_count"`
+	DueAt       string `json:"due_at"`
+}
+
+func collectionResponse(info *database.CollectionInfo) CollectionResponse {
+	return CollectionResponse{
+		ID:          info.ID,
+		Name:        info.Name,
+		Description: info.Description,
+		WordCount:   info.WordCount,
+		CreatedAt:   info.CreatedAt.String(),
+		UpdatedAt:   info.UpdatedAt.String(),
+	}
+}
+
+// wordFilter reads the optional deck and tag query parameters (IDs) of list endpoints.
+// 他のユーザーのデッキやタグを指定した場合は単語が見つからないだけになる
+func wordFilter(c echo.Context) (database.WordFilter, bool) {
+	var filter database.WordFilter
+
+	for param, id := range map[string]*uint{"deck": &filter.DeckID, "tag": &filter.TagID} {
+		value := c.QueryParam(param)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.ParseUint(value, 10, 0)
+		if err != nil || n == 0 {
+			return filter, false
+		}
+		*id = uint(n)
+	}
+
+	return filter, true
+}
+
+// pathID reads the numeric :id path parameter of a deck, tag or review session.
+func pathID(c echo.Context) (uint, bool) {
+	n, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil || n == 0 {
+		return 0, false
+	}
+	return uint(n), true
+}
+
+// collectionInput validates the name and description of a deck or tag.
+func collectionInput(c echo.Context) (database.CollectionInput, string) {
+	var req CollectionRequest
+	if err := c.Bind(&req); err != nil {
+		log.Printf("Failed to bind request: %v", err)
+		return database.CollectionInput{}, "リクエスト不備"
+	}
+
+	input := database.CollectionInput{
+		Name:        strings.Join(strings.Fields(req.Name), " "),
+		Description: strings.TrimSpace(req.Description),
+	}
+	switch {
+	case input.Name == "":
+		return input, "名前が必要です"
+	case utf8.RuneCountInString(input.Name) > maxCollectionNameLength:
+		return input, fmt.Sprintf("名前は%d文字以内で入力してください", maxCollectionNameLength)
+	case utf8.RuneCountInString(input.Description) > maxCollectionDescriptionLength:
+		return input, fmt.Sprintf("説明は%d文字以内で入力してください", maxCollectionDescriptionLength)
+	}
+	return input, ""
+}
+
+// ListCollectionsHandler handles GET /api/decks and /api/tags - returns the user's decks or tags ordered by name
+func (s *Server) ListCollectionsHandler(kind collectionKind) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// Get user ID from context (set by auth middleware)
+		userID, ok := c.Get(string(auth.UserIDContextKey)).(string)
+		if !ok {
+			log.Println("User ID not found in context")
+			return c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Message: "ユーザーIDが見つかりません",
+			})
+		}
+
+		infos, err := s.db.ListCollections(kind.col, userID)
+		if err != nil {
+			log.Printf("Failed to fetch %s: %v", kind.label, err)
+			return c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Message: "サーバーエラー",
+			})
+		}
+
+		response := []CollectionResponse{}
+		for i := range infos {
+			response = append(response, collectionResponse(&infos[i]))
+		}
+
+		return c.JSON(http.StatusOK, response)
+	}
+}
+
+// CreateCollectionHandler handles POST /api/decks and /api/tags - creates a deck or tag
+func (s *Server) CreateCollectionHandler(kind collectionKind) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// Get user ID from context (set by auth middleware)
+		userID, ok := c.Get(string(auth.UserIDContextKey)).(string)
+		if !ok {
+			log.Println("User ID not found in context")
+			return c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Message: "ユーザーIDが見つかりません",
+			})
+		}
+
+		input, message := collectionInput(c)
+		if message != "" {
+			return c.JSON(http.StatusBadRequest, ErrorResponse{
+				Message: message,
+			})
+		}
+
+		info, err := s.db.CreateCollection(kind.col, userID, input)
+		if errors.Is(err, database.ErrNameTaken) {
+			return c.JSON(http.StatusConflict, ErrorResponse{
+				Message: fmt.Sprintf("同じ名前の%sがすでにあります", kind.label),
+			})
+		}
+		if err != nil {
+			log.Printf("Failed to create %s: %v", kind.label, err)
+			return c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Message: "サーバーエラー",
+			})
+		}
+
+		return c.JSON(http.StatusCreated, collectionResponse(info))
+	}
+}
+
+// GetCollectionHandler handles GET /api/decks/:id and /api/tags/:id - returns a deck or tag
+func (s *Server) GetCollectionHandler(kind collectionKind) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// Get user ID from context (set by auth middleware)
+		userID, ok := c.Get(string(auth.UserIDContextKey)).(string)
+		if !ok {
+			log.Println("User ID not found in context")
+			return c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Message: "ユーザーIDが見つかりません",
+			})
+		}
+
+		id, ok := pathID(c)
+		if !ok {
+			return c.JSON(http.StatusBadRequest, ErrorResponse{
+				Message: "IDが不正です",
+			})
+		}
+
+		info, err := s.db.GetCollection(kind.col, userID, id)
+		if err != nil {
+			log.Printf("Failed to fetch %s: %v", kind.label, err)
+			return c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Message: "サーバーエラー",
+			})
+		}
+		if info == nil {
+			return c.JSON(http.StatusNotFound, ErrorResponse{
+				Message: kind.label + "が見つかりません",
+			})
+		}
+
+		return c.JSON(http.StatusOK, collectionResponse(info))
+	}
+}
+
+// UpdateCollectionHandler handles PUT /api/decks/:id and /api/tags/:id - renames a deck or tag and replaces its description
+func (s *Server) UpdateCollectionHandler(kind collectionKind) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// Get user ID from context (set by auth middleware)
+		userID, ok := c.Get(string(auth.UserIDContextKey)).(string)
+		if !ok {
+			log.Println("User ID not found in context")
+			return c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Message: "ユーザーIDが見つかりません",
+			})
+		}
+
+		id, ok := pathID(c)
+		if !ok {
+			return c.JSON(http.StatusBadRequest, ErrorResponse{
+				Message: "IDが不正です",
+			})
+		}
+
+		input, message := collectionInput(c)
+		if message != "" {
+			return c.JSON(http.StatusBadRequest, ErrorResponse{
+				Message: message,
+			})
+		}
+
+		info, err := s.db.UpdateCollection(kind.col, userID, id, input)
+		if errors.Is(err, database.ErrNameTaken) {
+			return c.JSON(http.StatusConflict, ErrorResponse{
+				Message: fmt.Sprintf("同じ名前の%sがすでにあります", kind.label),
+			})
+		}
+		if err != nil {
+			log.Printf("Failed to update %s: %v", kind.label, err)
+			return c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Message: "サーバーエラー",
+			})
+		}
+		if info == nil {
+			return c.JSON(http.StatusNotFound, ErrorResponse{
+				Message: kind.label + "が見つかりません",
+			})
+		}
+
+		return c.JSON(http.StatusOK, collectionResponse(info))
+	}
+}
+
+// DeleteCollectionHandler handles DELETE /api/decks/:id and /api/tags/:id - deletes a deck or tag but keeps its words
+func (s *Server) DeleteCollectionHandler(kind collectionKind) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// Get user ID from context (set by auth middleware)
+		userID, ok := c.Get(string(auth.UserIDContextKey)).(string)
+		if !ok {
+			log.Println("User ID not found in context")
+			return c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Message: "ユーザーIDが見つかりません",
+			})
+		}
+
+		id, ok := pathID(c)
+		if !ok {
+			return c.JSON(http.StatusBadRequest, ErrorResponse{
+				Message: "IDが不正です",
+			})
+		}
+
+		deleted, err := s.db.DeleteCollection(kind.col, userID, id)
+		if err != nil {
+			log.Printf("Failed to delete %s: %v", kind.label, err)
+			return c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Message: "サーバーエラー",
+			})
+		}
+		if !deleted {
+			return c.JSON(http.StatusNotFound, ErrorResponse{
+				Message: kind.label + "が見つかりません",
+			})
+		}
+
+		return c.JSON(http.StatusOK, map[string]string{
+			"message": kind.label + "を削除しました"})
+	}
+}
+
+// CollectionWordsHandler handles GET /api/decks/:id/words and /api/tags/:id/words - returns the words in a deck or tag
+func (s *Server) CollectionWordsHandler(kind collectionKind) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// Get user ID from context (set by auth middleware)
+		userID, ok := c.Get(string(auth.UserIDContextKey)).(string)
+		if !ok {
+			log.Println("User ID not found in context")
+			return c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Message: "ユーザーIDが見つかりません",
+			})
+		}
+
+		id, ok := pathID(c)
+		if !ok {
+			return c.JSON(http.StatusBadRequest, ErrorResponse{
+				Message: "IDが不正です",
+			})
+		}
+
+		info, err := s.db.GetCollection(kind.col, userID, id)
+		if err != nil {
+			log.Printf("Failed to fetch %s: %v", kind.label, err)
+			return c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Message: "サーバーエラー",
+			})
+		}
+		if info == nil {
+			return c.JSON(http.StatusNotFound, ErrorResponse{
+				Message: kind.label + "が見つかりません",
+			})
+		}
+
+		words, err := s.db.CollectionWords(kind.col, userID, id)
+		if err != nil {
+			log.Printf("Failed to fetch words of %s: %v", kind.label, err)
+			return c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Message: "サーバーエラー",
+			})
+		}
+
+		response := []CollectionWordResponse{}
+		for _, word := range words {
+			response = append(response, CollectionWordResponse{
+				Word:        word.Word,
+				Lang:        word.Lang,
+				Kind:        word.Kind,
+				Level:       word.Level,
+				SearchCount: word.SearchCount,
+				ReviewCount: word.ReviewCount,
+				DueAt:       word.DueAt.String(),
+			})
+		}
+
+		return c.JSON(http.StatusOK, response)
+	}
+}
+
+// AddCollectionWordHandler handles PUT /api/decks/:id/words/:word and /api/tags/:id/words/:word -
+// puts a saved word in a deck or tag
+func (s *Server) AddCollectionWordHandler(kind collectionKind) echo.HandlerFunc {
+	return s.collectionWordHandler(kind, func(userID string, id uint, lang dictionary.Direction, word string) error {
+		return s.db.AddCollectionWord(kind.col, userID, id, string(lang), word)
+	}, "%sを%sに追加しました")
+}
+
+// RemoveCollectionWordHandler handles DELETE /api/decks/:id/words/:word and /api/tags/:id/words/:word -
+// takes a word out of a deck or tag
+func (s *Server) RemoveCollectionWordHandler(kind collectionKind) echo.HandlerFunc {
+	return s.collectionWordHandler(kind, func(userID string, id uint, lang dictionary.Direction, word string) error {
+		return s.db.RemoveCollectionWord(kind.col, userID, id, string(lang), word)
+	}, "%sを%sから外しました")
+}
+
+// collectionWordHandler validates the deck or tag and the saved word of a membership change and applies it.
+// message receives the word and the name of the deck or tag
+func (s *Server) collectionWordHandler(kind collectionKind, apply func(userID string, id uint, lang dictionary.Direction, word string) error, message string) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// Get user ID from context (set by auth middleware)
+		userID, ok := c.Get(string(auth.UserIDContextKey)).(string)
+		if !ok {
+			log.Println("User ID not found in context")
+			return c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Message: "ユーザーIDが見つかりません",
+			})
+		}
+
+		id, ok := pathID(c)
+		if !ok {
+			return c.JSON(http.StatusBadRequest, ErrorResponse{
+				Message: "IDが不正です",
+			})
+		}
+
+		word := c.Param("word")
+		if word == "" {
+			return c.JSON(http.StatusBadRequest, ErrorResponse{
+				Message: "単語が指定されていません",
+			})
+		}
+
+		lang, ok := dictionary.ParseLang(c.QueryParam("lang"))
+		if !ok {
+			return c.JSON(http.StatusBadRequest, ErrorResponse{
+				Message: "言語の指定が不正です",
+			})
+		}
+		word = normalize.Word(word, lang.Source())
+
+		info, err := s.db.GetCollection(kind.col, userID, id)
+		if err != nil {
+			log.Printf("Failed to fetch %s: %v", kind.label, err)
+			return c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Message: "サーバーエラー",
+			})
+		}
+		if info == nil {
+			return c.JSON(http.StatusNotFound, ErrorResponse{
+				Message: kind.label + "が見つかりません",
+			})
+		}
+
+		// 単語帳に登録済みの単語だけをデッキ・タグに入れられる
+		if wordRecord, _ := s.db.GetWordInfo(userID, string(lang), word); wordRecord == nil {
+			return c.JSON(http.StatusNotFound, ErrorResponse{
+				Message: "単語が見つかりません",
+			})
+		}
+
+		if err := apply(userID, id, lang, word); err != nil {
+			log.Printf("Failed to update words of %s: %v", kind.label, err)
+			return c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Message: "サーバーエラー",
+			})
+		}
+
+		return c.JSON(http.StatusOK, map[string]string{
+			"message": fmt.Sprintf(message, word, info.Name)})
+	}
+}
@@ -2,12 +2,22 @@ package server
 
 import (
 	"fmt"
-	"io"
 	"log"
 	"net/http"
-	"net/url"
-	"sync"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 	"tsumitan/internal/auth"
+	"tsumitan/internal/corpus"
+	"tsumitan/internal/database"
+	"tsumitan/internal/dictionary"
+	"tsumitan/internal/models"
+	"tsumitan/internal/normalize"
+	"tsumitan/internal/scheduler"
+	"tsumitan/internal/wordlist"
+	"tsumitan/internal/wordnet"
+	"unicode/utf8"
 
 	"github.com/labstack/echo/v4"
 )
@@ -21,67 +31,52 @@ func (s *Server) HelloWorldHandler(c echo.Context) error {
 }
 
 func (s *Server) healthHandler(c echo.Context) error {
-	return c.JSON(http.StatusOK, s.db.Health())
-}
-
-// キャッシュ生成用
-var (
-	wordCache = make(map[string]string)
-	cacheMu   sync.RWMutex
-)
-
-type DictionaryResponse struct {
-	Word     string `json:"word"`
-	Meanings string `json:"meanings"`
-}
+	stats := s.db.Health()
 
-// 単語の意味を取得する（キャッシュを用いる）
-func FetchWordMeaning(word string) (string, error) {
-	if word == "" {
-		return "", fmt.Errorf("単語が指定されていません")
-	}
-
-	cacheMu.RLock()
-	if meanings, found := wordCache[word]; found {
-		cacheMu.RUnlock()
-		return meanings, nil
-	}
-	cacheMu.RUnlock()
-
-	// 辞書APIにリクエストを送信
-	url := "https://api.excelapi.org/dictionary/enja?word=" + url.QueryEscape(word)
-	resp, err := http.Get(url)
-	if err != nil {
-		return "", fmt.Errorf("辞書APIリクエスト失敗: %w", err)
-	}
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			log.Printf("response body close error: %v", err)
+	// 辞書プロバイダのサーキットブレーカーの状態も返す
+	if reporter, ok := s.dict.(dictionary.HealthReporter); ok {
+		for key, value := range reporter.Health() {
+			stats["dictionary_"+key] = value
 		}
-	}()
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("辞書APIステータスエラー: %d", resp.StatusCode)
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("辞書APIレスポンス読み取りエラー: %w", err)
 	}
 
-	meanings := string(body)
-
-	// キャッシュに保存
-	cacheMu.Lock()
-	wordCache[word] = meanings
-	cacheMu.Unlock()
+	return c.JSON(http.StatusOK, stats)
+}
 
-	return meanings, nil
+type DictionaryResponse struct {
+	Word      string `json:"word"`
+	Lang      string `json:"lang"`
+	Direction string `json:"direction"`
+	// Headword is the English word recorded by POST /api/search
+	Headword string `json:"headword"`
+	// Kind is "word", "phrasal_verb" or "phrase"
+	Kind string `json:"kind"`
+	// Fallback is the head word looked up when a phrase has no entry of its own
+	Fallback string `json:"fallback,omitempty"`
+	// Meanings is the unparsed dictionary text, kept for compatibility
+	Meanings      string             `json:"meanings"`
+	PartsOfSpeech []string           `json:"parts_of_speech"`
+	Senses        []dictionary.Sense `json:"senses"`
+	// CustomDefinition is the learner's own definition of the saved headword, shown instead of Senses
+	CustomDefinition string `json:"custom_definition,omitempty"`
+	Pronunciation    string `json:"pronunciation,omitempty"`
+	// IPA and ARPAbet are the pronunciation of the headword from the pronouncing dictionary
+	IPA      string               `json:"ipa,omitempty"`
+	ARPAbet  string               `json:"arpabet,omitempty"`
+	Examples []dictionary.Example `json:"examples,omitempty"`
 }
 
 // SearchRequest represents the request body for search endpoint
 type SearchRequest struct {
 	Word string `json:"word"`
+	// Optional context of where the word was found
+	SourceURL   string `json:"source_url"`
+	SourceTitle string `json:"source_title"`
+	Sentence    string `json:"sentence"`
+	// Lang is the language pair being learned (defaults to "en-ja")
+	Lang string `json:"lang"`
+	// Direction is Lang, its reverse, or empty to detect it from the word
+	Direction string `json:"direction"`
 }
 
 // ErrorResponse represents error response structure
@@ -89,6 +84,40 @@ type ErrorResponse struct {
 	Message string `json:"message"`
 }
 
+// NotFoundResponse is returned when a word has no dictionary entry
+type NotFoundResponse struct {
+	Message string `json:"message"`
+	// Suggestions are headwords the word may be a misspelling of, most likely first
+	Suggestions []string `json:"suggestions"`
+}
+
+// maxSuggestions is the number of spelling suggestions returned with a 404
+const maxSuggestions = 5
+
+// wordNotFound responds with 404 and spelling suggestions for word from the offline dictionary.
+func (s *Server) wordNotFound(c echo.Context, word string, dir dictionary.Direction) error {
+	suggestions := []string{}
+	if index, ok := s.suggestions[dir]; ok {
+		suggestions = index.Corrections(word, maxSuggestions)
+	}
+
+	return c.JSON(http.StatusNotFound, NotFoundResponse{
+		Message:     "意味の取得に失敗しました",
+		Suggestions: suggestions,
+	})
+}
+
+// langFilter reads the optional lang query parameter of list endpoints.
+// 指定がない場合はすべての言語の単語を対象にするため空文字列を返す
+func langFilter(c echo.Context) (string, bool) {
+	value := c.QueryParam("lang")
+	if value == "" {
+		return "", true
+	}
+	lang, ok := dictionary.ParseLang(value)
+	return string(lang), ok
+}
+
 // SearchHandler handles POST /api/search - records a word search and returns no meaning
 func (s *Server) SearchHandler(c echo.Context) error {
 	// Get user ID from context (set by auth middleware)
@@ -116,32 +145,63 @@ func (s *Server) SearchHandler(c echo.Context) error {
 		})
 	}
 
-	// 単語の意味が存在するか確認
-	meanings, err := FetchWordMeaning(req.Word)
-	if err != nil || meanings == "" {
-		log.Printf("意味取得失敗: %v", err)
-		return c.JSON(http.StatusNotFound, ErrorResponse{
-			Message: "意味の取得に失敗しました",
+	lang, ok := dictionary.ParseLang(req.Lang)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "言語の指定が不正です",
 		})
 	}
 
+	direction, ok := dictionary.ParseDirection(req.Direction, lang, req.Word)
+	if !ok || !s.dict.Supports(direction) {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "対応していない検索方向です",
+		})
+	}
+
+	if len(strings.Fields(req.Word)) > normalize.MaxPhraseWords {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: fmt.Sprintf("フレーズは%d語以内で入力してください", normalize.MaxPhraseWords),
+		})
+	}
+
+	// 単語の意味が存在するか確認（活用形は原形で引く）
+	query := normalize.Word(req.Word, direction.Source())
+	entry, err := s.dict.Lookup(c.Request().Context(), dictionary.Query{Word: query, Direction: direction})
+	if err != nil || len(entry.Senses) == 0 {
+		log.Printf("意味取得失敗: %v", err)
+		return s.wordNotFound(c, query, direction)
+	}
+
+	// 逆引き（和英など）の場合は学習中の言語の単語を単語帳に記録する
+	word := normalize.Word(entry.Headword, lang.Source())
+
+	search := database.SearchInput{
+		SearchedAt:  time.Now(),
+		SurfaceForm: req.Word,
+		SourceURL:   req.SourceURL,
+		SourceTitle: req.SourceTitle,
+		Sentence:    req.Sentence,
+	}
+
 	// Record search in database
-	if err := s.db.CreateOrUpdateWordSearch(userID, req.Word); err != nil {
+	if err := s.db.CreateOrUpdateWordSearch(userID, string(lang), word, search); err != nil {
 		log.Printf("Failed to record search: %v", err)
 		return c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Message: "サーバーエラー",
 		})
 	}
 
-	log.Printf("Search recorded for user %s, word: %s", userID, req.Word)
+	log.Printf("Search recorded for user %s, word: %s (%s)", userID, word, direction)
 
 	// Return success response (no meaning returned)
 	return c.JSON(http.StatusOK, map[string]string{
 		"message": "検索が記録されました",
+		"word":    word,
 	})
 }
 
-// GetWordMeaningHandler handles GET /api/search?word={word} - returns word meaning without incrementing search count
+// GetWordMeaningHandler handles GET /api/search?word={word}&direction={direction} - returns word meaning without incrementing search count
 func (s *Server) GetWordMeaningHandler(c echo.Context) error {
 	// Get user ID from context (set by auth middleware)
 	userID, ok := c.Get(string(auth.UserIDContextKey)).(string)
@@ -160,27 +220,210 @@ func (s *Server) GetWordMeaningHandler(c echo.Context) error {
 		})
 	}
 
-	meanings, err := FetchWordMeaning(word)
-	if err != nil || meanings == "" {
+	lang, ok := dictionary.ParseLang(c.QueryParam("lang"))
+	if !ok {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "言語の指定が不正です",
+		})
+	}
+
+	// 検索方向が指定されていない場合は入力から判定する
+	direction, ok := dictionary.ParseDirection(c.QueryParam("direction"), lang, word)
+	if !ok || !s.dict.Supports(direction) {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "対応していない検索方向です",
+		})
+	}
+
+	if len(strings.Fields(word)) > normalize.MaxPhraseWords {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: fmt.Sprintf("フレーズは%d語以内で入力してください", normalize.MaxPhraseWords),
+		})
+	}
+
+	query := normalize.Word(word, direction.Source())
+	entry, err := s.dict.Lookup(c.Request().Context(), dictionary.Query{Word: query, Direction: direction})
+	if err != nil || len(entry.Senses) == 0 {
 		log.Printf("意味取得失敗: %v", err)
-		return c.JSON(http.StatusNotFound, ErrorResponse{Message: "意味の取得に失敗しました"})
+		return s.wordNotFound(c, query, direction)
 	}
 
 	log.Printf("Word meaning fetched for user %s, word: %s (no search count increment)", userID, word)
 
+	headword := normalize.Word(entry.Headword, lang.Source())
+	// 入力された語形（"ran" など）の発音を優先し、なければ見出し語の発音を返す
+	pronunciation := s.pronunciation(lang, normalize.Fold(word, direction.Source()), headword)
+
+	// 単語帳にない単語は自分の定義もないので、見つからない場合のエラーは無視する
+	var customDefinition string
+	if wordRecord, err := s.db.GetWordInfo(userID, string(lang), headword); err == nil && wordRecord != nil {
+		customDefinition = wordRecord.CustomDefinition
+	}
+
 	// Return word meaning without incrementing search count
 	return c.JSON(http.StatusOK, DictionaryResponse{
-		Word:     word,
-		Meanings: meanings, // Assuming we return the first meaning
+		Word:             word,
+		Lang:             string(lang),
+		Direction:        string(direction),
+		Headword:         headword,
+		Kind:             string(normalize.KindOf(headword, lang.Source())),
+		Fallback:         entry.Fallback,
+		Meanings:         entry.Raw,
+		PartsOfSpeech:    entry.PartsOfSpeech,
+		Senses:           entry.Senses,
+		CustomDefinition: customDefinition,
+		Pronunciation:    entry.Pronunciation,
+		IPA:              pronunciation.IPA,
+		ARPAbet:          pronunciation.ARPAbet,
+		Examples:         entry.Examples,
 	})
 }
 
+type SuggestResponse struct {
+	Query     string `json:"query"`
+	Direction string `json:"direction"`
+	// Suggestions are the user's saved words first, then dictionary headwords by frequency
+	Suggestions []SuggestionItem `json:"suggestions"`
+}
+
+type SuggestionItem struct {
+	Word string `json:"word"`
+	// Saved reports whether the word is already in the user's word list
+	Saved bool `json:"saved"`
+}
+
+const (
+	defaultSuggestLimit = 10
+	maxSuggestLimit     = 50
+)
+
+// SuggestHandler handles GET /api/search/suggest?q={prefix} - returns headwords starting with the prefix
+func (s *Server) SuggestHandler(c echo.Context) error {
+	// Get user ID from context (set by auth middleware)
+	userID, ok := c.Get(string(auth.UserIDContextKey)).(string)
+	if !ok {
+		log.Println("User ID not found in context")
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: "ユーザーIDが見つかりません",
+		})
+	}
+
+	query := c.QueryParam("q")
+	if strings.TrimSpace(query) == "" {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "検索語が必要です",
+		})
+	}
+
+	limit := defaultSuggestLimit
+	if value := c.QueryParam("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxSuggestLimit {
+			return c.JSON(http.StatusBadRequest, ErrorResponse{
+				Message: fmt.Sprintf("limit は1から%dまでの整数で指定してください", maxSuggestLimit),
+			})
+		}
+		limit = n
+	}
+
+	lang, ok := dictionary.ParseLang(c.QueryParam("lang"))
+	if !ok {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "言語の指定が不正です",
+		})
+	}
+	direction := dictionary.DetectDirection(query, lang)
+	// 入力途中の語なので原形にはしない
+	prefix := normalize.Fold(query, direction.Source())
+
+	response := SuggestResponse{
+		Query:       prefix,
+		Direction:   string(direction),
+		Suggestions: []SuggestionItem{},
+	}
+	seen := map[string]bool{}
+
+	// 単語帳の単語は学習中の言語で入力している場合のみ候補にする
+	if direction == lang {
+		saved, err := s.db.WordsWithPrefix(userID, string(lang), prefix, limit)
+		if err != nil {
+			log.Printf("Failed to fetch saved words: %v", err)
+			return c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Message: "サーバーエラー",
+			})
+		}
+		for _, word := range saved {
+			seen[word] = true
+			response.Suggestions = append(response.Suggestions, SuggestionItem{Word: word, Saved: true})
+		}
+	}
+
+	if index, ok := s.suggestions[direction]; ok {
+		// 単語帳の単語と重複する分だけ多めに取る
+		for _, word := range index.Prefix(prefix, limit+len(seen)) {
+			if len(response.Suggestions) == limit {
+				break
+			}
+			if seen[word] {
+				continue
+			}
+			response.Suggestions = append(response.Suggestions, SuggestionItem{Word: word})
+		}
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
 type PendingResponse struct {
-	Word        string `json:"word"`
-	SearchCount int    `json:"search_count"`
+	Word          string `json:"word"`
+	Lang          string `json:"lang"`
+	Kind          string `json:"kind"`
+	FrequencyRank int    `json:"frequency_rank,omitempty"`
+	Level         string `json:"level,omitempty"`
+	// Notes, Mnemonic and CustomDefinition are written by the learner
+	Notes            string `json:"notes"`
+	Mnemonic         string `json:"mnemonic"`
+	CustomDefinition string `json:"custom_definition"`
+	SearchCount      int    `json:"search_count"`
+	DueAt            string `json:"due_at"`
+	// Starred words come first in the queue
+	Starred bool `json:"starred"`
 }
 
-// GetPendingReviewsHandler handles GET /api/review/pending - returns words pending review for the user
+// pendingOptions reads the level filter (e.g. "A1,A2"), the starred filter and sort order of the pending queue.
+func pendingOptions(c echo.Context) (database.PendingOptions, bool) {
+	opts := database.PendingOptions{Order: database.OrderDue}
+
+	if value := c.QueryParam("level"); value != "" {
+		for _, level := range strings.Split(value, ",") {
+			level = strings.ToUpper(strings.TrimSpace(level))
+			if !wordlist.ValidLevel(level) {
+				return opts, false
+			}
+			opts.Levels = append(opts.Levels, level)
+		}
+	}
+
+	if value := c.QueryParam("starred"); value != "" {
+		starred, err := strconv.ParseBool(value)
+		if err != nil {
+			return opts, false
+		}
+		opts.StarredOnly = starred
+	}
+
+	switch order := database.PendingOrder(c.QueryParam("sort")); order {
+	case "":
+	case database.OrderDue, database.OrderLevel, database.OrderFrequency:
+		opts.Order = order
+	default:
+		return opts, false
+	}
+
+	return opts, true
+}
+
+// GetPendingReviewsHandler handles GET /api/review/pending - returns words whose review is due for the user
 func (s *Server) GetPendingReviewsHandler(c echo.Context) error {
 	// Get user ID from context (set by auth middleware)
 	userID, ok := c.Get(string(auth.UserIDContextKey)).(string)
@@ -192,8 +435,29 @@ func (s *Server) GetPendingReviewsHandler(c echo.Context) error {
 	}
 
 	// Fetch pending reviews from database
-	// データベースから未レビューの単語を取得
-	pendingReviews, err := s.db.PendingWordSearch(userID)
+	// データベースから復習期限を迎えた単語を取得
+	lang, ok := langFilter(c)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "言語の指定が不正です",
+		})
+	}
+
+	opts, ok := pendingOptions(c)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "レベル・スター・並び順の指定が不正です",
+		})
+	}
+
+	opts.WordFilter, ok = wordFilter(c)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "デッキまたはタグの指定が不正です",
+		})
+	}
+
+	pendingReviews, err := s.db.PendingWordSearch(userID, lang, time.Now(), opts)
 	if err != nil {
 		log.Printf("Failed to fetch pending reviews: %v", err)
 		return c.JSON(http.StatusInternalServerError, ErrorResponse{
@@ -206,8 +470,17 @@ func (s *Server) GetPendingReviewsHandler(c echo.Context) error {
 
 	for _, review := range pendingReviews {
 		response = append(response, PendingResponse{
-			Word:        review.Word,
-			SearchCount: review.SearchCount,
+			Word:             review.Word,
+			Lang:             review.Lang,
+			Kind:             review.Kind,
+			FrequencyRank:    review.FrequencyRank,
+			Level:            review.Level,
+			Notes:            review.Notes,
+			Mnemonic:         review.Mnemonic,
+			CustomDefinition: review.CustomDefinition,
+			SearchCount:      review.SearchCount,
+			DueAt:            review.DueAt.String(),
+			Starred:          review.Starred,
 		})
 	}
 
@@ -217,6 +490,58 @@ func (s *Server) GetPendingReviewsHandler(c echo.Context) error {
 
 type ReviewRequest struct {
 	Word string `json:"word"`
+	// Lang is the language pair of the word (defaults to "en-ja")
+	Lang string `json:"lang"`
+	// Grade is one of "again", "hard", "good" or "easy" (defaults to "good")
+	Grade string `json:"grade"`
+	// ResponseTimeMs is the optional time the learner took to answer
+	ResponseTimeMs *int `json:"response_time_ms"`
+	// Device is the optional client device name (defaults to the User-Agent header)
+	Device string `json:"device"`
+}
+
+// reviewInput validates a review answer and returns the language pair and headword of the word
+// with the answer. message is the error shown to the learner, "" when the request is valid
+func reviewInput(c echo.Context, req ReviewRequest) (dictionary.Direction, string, database.ReviewInput, string) {
+	// Validate required fields
+	if req.Word == "" {
+		return "", "", database.ReviewInput{}, "必須フィールドが不足しています"
+	}
+
+	lang, ok := dictionary.ParseLang(req.Lang)
+	if !ok {
+		return "", "", database.ReviewInput{}, "言語の指定が不正です"
+	}
+
+	word := normalize.Word(req.Word, lang.Source())
+
+	grade := scheduler.GradeGood
+	if req.Grade != "" {
+		parsed, err := scheduler.ParseGrade(req.Grade)
+		if err != nil {
+			return "", "", database.ReviewInput{}, "評価の値が不正です"
+		}
+		grade = parsed
+	}
+
+	device := req.Device
+	if device == "" {
+		device = c.Request().UserAgent()
+	}
+
+	review := database.ReviewInput{
+		Grade:      grade,
+		ReviewedAt: time.Now(),
+		Device:     device,
+	}
+	if req.ResponseTimeMs != nil {
+		if *req.ResponseTimeMs < 0 {
+			return "", "", database.ReviewInput{}, "回答時間の値が不正です"
+		}
+		review.ResponseTimeMs = *req.ResponseTimeMs
+	}
+
+	return lang, word, review, ""
 }
 
 // ReviewHandler handles PATCH /api/review - records a review for a word
@@ -239,32 +564,44 @@ func (s *Server) ReviewHandler(c echo.Context) error {
 		})
 	}
 
-	// Validate required fields
-	if req.Word == "" {
+	lang, word, review, message := reviewInput(c, req)
+	if message != "" {
 		return c.JSON(http.StatusBadRequest, ErrorResponse{
-			Message: "必須フィールドが不足しています",
+			Message: message,
 		})
 	}
 
-	// Update review count in database
-	if err := s.db.UpdateWordReview(userID, req.Word); err != nil {
+	// Update review count and schedule in database
+	if err := s.db.UpdateWordReview(userID, string(lang), word, s.reviewScheduler(userID), review); err != nil {
 		log.Printf("Failed to update review: %v", err)
 		return c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Message: "サーバーエラー",
 		})
 	}
 
-	log.Printf("Review updated for user %s, word: %s", userID, req.Word)
+	log.Printf("Review updated for user %s, word: %s, grade: %s", userID, word, review.Grade)
 
 	return c.JSON(http.StatusOK, map[string]string{
 		"message": "復習が記録されました。"})
 }
 
 type ReviewHistoryResponse struct {
-	Word         string `json:"word"`
-	SearchCount  int    `json:"search_count"`
-	ReviewCount  int    `json:"review_count"`
-	LastReviewed string `json:"last_reviewed"`
+	Word          string `json:"word"`
+	Lang          string `json:"lang"`
+	FrequencyRank int    `json:"frequency_rank,omitempty"`
+	Level         string `json:"level,omitempty"`
+	// Notes, Mnemonic and CustomDefinition are written by the learner
+	Notes            string `json:"notes"`
+	Mnemonic         string `json:"mnemonic"`
+	CustomDefinition string `json:"custom_definition"`
+	SearchCount      int    `json:"search_count"`
+	ReviewCount      int    `json:"review_count"`
+	LapseCount       int    `json:"lapse_count"`
+	LastReviewed     string `json:"last_reviewed"`
+	// Archived is true for words marked as mastered, which are no longer in the review queue
+	Archived  bool `json:"archived"`
+	Suspended bool `json:"suspended"`
+	Starred   bool `json:"starred"`
 }
 
 // ReviewHistoryHandler handles GET /api/review/history - returns review history for the user
@@ -280,7 +617,21 @@ func (s *Server) ReviewHistoryHandler(c echo.Context) error {
 
 	// Fetch pending reviews from database
 	// データベースから未レビューの単語を取得
-	reviewedRecords, err := s.db.ReviewedWordSearch(userID)
+	lang, ok := langFilter(c)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "言語の指定が不正です",
+		})
+	}
+
+	filter, ok := wordFilter(c)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "デッキまたはタグの指定が不正です",
+		})
+	}
+
+	reviewedRecords, err := s.db.ReviewedWordSearch(userID, lang, filter)
 	if err != nil {
 		log.Printf("Failed to fetch pending reviews: %v", err)
 		return c.JSON(http.StatusInternalServerError, ErrorResponse{
@@ -293,10 +644,20 @@ func (s *Server) ReviewHistoryHandler(c echo.Context) error {
 
 	for _, review := range reviewedRecords {
 		response = append(response, ReviewHistoryResponse{
-			Word:         review.Word,
-			SearchCount:  review.SearchCount,
-			ReviewCount:  review.ReviewCount,
-			LastReviewed: review.LastReviewed.String(),
+			Word:             review.Word,
+			Lang:             review.Lang,
+			FrequencyRank:    review.FrequencyRank,
+			Level:            review.Level,
+			Notes:            review.Notes,
+			Mnemonic:         review.Mnemonic,
+			CustomDefinition: review.CustomDefinition,
+			SearchCount:      review.SearchCount,
+			ReviewCount:      review.ReviewCount,
+			LapseCount:       review.LapseCount,
+			LastReviewed:     review.LastReviewed.String(),
+			Archived:         review.ArchivedAt != nil,
+			Suspended:        review.Suspended,
+			Starred:          review.Starred,
 		})
 	}
 
@@ -305,10 +666,29 @@ func (s *Server) ReviewHistoryHandler(c echo.Context) error {
 }
 
 type WordDetailResponse struct {
-	Word         string `json:"word"`
-	SearchCount  int    `json:"search_count"`
-	ReviewCount  int    `json:"review_count"`
-	LastReviewed string `json:"last_reviewed"`
+	Word          string `json:"word"`
+	Lang          string `json:"lang"`
+	Kind          string `json:"kind"`
+	FrequencyRank int    `json:"frequency_rank,omitempty"`
+	Level         string `json:"level,omitempty"`
+	IPA           string `json:"ipa,omitempty"`
+	ARPAbet       string `json:"arpabet,omitempty"`
+	// Notes, Mnemonic and CustomDefinition are written by the learner
+	Notes            string  `json:"notes"`
+	Mnemonic         string  `json:"mnemonic"`
+	CustomDefinition string  `json:"custom_definition"`
+	SearchCount      int     `json:"search_count"`
+	ReviewCount      int     `json:"review_count"`
+	LapseCount       int     `json:"lapse_count"`
+	LastReviewed     string  `json:"last_reviewed"`
+	EaseFactor       float64 `json:"ease_factor"`
+	IntervalDays     int     `json:"interval_days"`
+	DueAt            string  `json:"due_at"`
+	Archived         bool    `json:"archived"`
+	Suspended        bool    `json:"suspended"`
+	// BuriedUntil is set while the word is buried and left out of the queue
+	BuriedUntil string `json:"buried_until,omitempty"`
+	Starred     bool   `json:"starred"`
 }
 
 // GetWordHandler handles GET /api/word/:word - returns detailed word info for the user
@@ -329,8 +709,16 @@ func (s *Server) GetWordHandler(c echo.Context) error {
 		})
 	}
 
+	lang, ok := dictionary.ParseLang(c.QueryParam("lang"))
+	if !ok {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "言語の指定が不正です",
+		})
+	}
+	word = normalize.Word(word, lang.Source())
+
 	// Fetch word from database
-	wordRecord, err := s.db.GetWordInfo(userID, word)
+	wordRecord, err := s.db.GetWordInfo(userID, string(lang), word)
 	if wordRecord == nil {
 		return c.JSON(http.StatusNotFound, ErrorResponse{
 			Message: "単語が見つかりません",
@@ -343,14 +731,428 @@ func (s *Server) GetWordHandler(c echo.Context) error {
 		})
 	}
 
-	// Map database results to PendingResponse
-	response := WordDetailResponse{
-		Word:         wordRecord.Word,
-		SearchCount:  wordRecord.SearchCount,
-		ReviewCount:  wordRecord.ReviewCount,
-		LastReviewed: wordRecord.LastReviewed.String(),
+	// Return filtered response
+	return c.JSON(http.StatusOK, s.wordDetail(wordRecord))
+}
+
+// wordDetail maps a word record onto WordDetailResponse
+func (s *Server) wordDetail(wordRecord *models.Word) WordDetailResponse {
+	pronunciation := s.pronunciation(dictionary.Direction(wordRecord.Lang), wordRecord.Word)
+
+	return WordDetailResponse{
+		Word:             wordRecord.Word,
+		Lang:             wordRecord.Lang,
+		Kind:             wordRecord.Kind,
+		FrequencyRank:    wordRecord.FrequencyRank,
+		Level:            wordRecord.Level,
+		IPA:              pronunciation.IPA,
+		ARPAbet:          pronunciation.ARPAbet,
+		Notes:            wordRecord.Notes,
+		Mnemonic:         wordRecord.Mnemonic,
+		CustomDefinition: wordRecord.CustomDefinition,
+		SearchCount:      wordRecord.SearchCount,
+		ReviewCount:      wordRecord.ReviewCount,
+		LapseCount:       wordRecord.LapseCount,
+		LastReviewed:     wordRecord.LastReviewed.String(),
+		EaseFactor:       wordRecord.EaseFactor,
+		IntervalDays:     wordRecord.IntervalDays,
+		DueAt:            wordRecord.DueAt.String(),
+		Archived:         wordRecord.ArchivedAt != nil,
+		Suspended:        wordRecord.Suspended,
+		BuriedUntil:      buriedUntilString(wordRecord.BuriedUntil),
+		Starred:          wordRecord.Starred,
+	}
+}
+
+// buriedUntilString formats the end of a bury that has not passed yet, or returns "".
+func buriedUntilString(until *time.Time) string {
+	if until == nil || !until.After(time.Now()) {
+		return ""
+	}
+	return until.String()
+}
+
+type WordNotesRequest struct {
+	// Notes is free-form Markdown
+	Notes    *string `json:"notes"`
+	Mnemonic *string `json:"mnemonic"`
+	// CustomDefinition is shown instead of the dictionary gloss when it is not empty
+	CustomDefinition *string `json:"custom_definition"`
+}
+
+// 学習者が書く内容の最大文字数
+const (
+	maxNotesLength            = 10000
+	maxMnemonicLength         = 500
+	maxCustomDefinitionLength = 1000
+)
+
+// UpdateWordNotesHandler handles PUT and PATCH /api/word/:word - edits the notes, mnemonic and custom definition of a word.
+// PUT replaces all three fields (omitted fields are cleared), PATCH updates only the fields in the request
+func (s *Server) UpdateWordNotesHandler(c echo.Context) error {
+	// Get user ID from context (set by auth middleware)
+	userID, ok := c.Get(string(auth.UserIDContextKey)).(string)
+	if !ok {
+		log.Println("User ID not found in context")
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: "ユーザーIDが見つかりません",
+		})
+	}
+
+	word := c.Param("word")
+	if word == "" {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "単語が指定されていません",
+		})
+	}
+
+	lang, ok := dictionary.ParseLang(c.QueryParam("lang"))
+	if !ok {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "言語の指定が不正です",
+		})
+	}
+	word = normalize.Word(word, lang.Source())
+
+	var req WordNotesRequest
+	if err := c.Bind(&req); err != nil {
+		log.Printf("Failed to bind request: %v", err)
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "リクエスト不備",
+		})
+	}
+
+	fields := []struct {
+		value     **string
+		maxLength int
+		name      string
+	}{
+		{&req.Notes, maxNotesLength, "メモ"},
+		{&req.Mnemonic, maxMnemonicLength, "語呂合わせ"},
+		{&req.CustomDefinition, maxCustomDefinitionLength, "自分の定義"},
+	}
+	for _, field := range fields {
+		if *field.value == nil {
+			if c.Request().Method == http.MethodPut {
+				*field.value = new(string)
+			}
+			continue
+		}
+		if utf8.RuneCountInString(**field.value) > field.maxLength {
+			return c.JSON(http.StatusBadRequest, ErrorResponse{
+				Message: fmt.Sprintf("%sは%d文字以内で入力してください", field.name, field.maxLength),
+			})
+		}
+	}
+
+	wordRecord, err := s.db.UpdateWordNotes(userID, string(lang), word, database.NotesInput{
+		Notes:            req.Notes,
+		Mnemonic:         req.Mnemonic,
+		CustomDefinition: req.CustomDefinition,
+	})
+	if err != nil {
+		log.Printf("Failed to update notes: %v", err)
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: "サーバーエラー",
+		})
+	}
+	if wordRecord == nil {
+		return c.JSON(http.StatusNotFound, ErrorResponse{
+			Message: "単語が見つかりません",
+		})
+	}
+
+	log.Printf("Notes updated for user %s, word: %s", userID, word)
+
+	return c.JSON(http.StatusOK, s.wordDetail(wordRecord))
+}
+
+type ReviewEventResponse struct {
+	ReviewedAt           string `json:"reviewed_at"`
+	Grade                string `json:"grade"`
+	ResponseTimeMs       int    `json:"response_time_ms"`
+	PreviousIntervalDays int    `json:"previous_interval_days"`
+	NewIntervalDays      int    `json:"new_interval_days"`
+	Device               string `json:"device"`
+}
+
+// WordReviewsHandler handles GET /api/word/:word/reviews - returns the review log of a word for the user
+func (s *Server) WordReviewsHandler(c echo.Context) error {
+	// Get user ID from context (set by auth middleware)
+	userID, ok := c.Get(string(auth.UserIDContextKey)).(string)
+	if !ok {
+		log.Println("User ID not found in context")
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: "ユーザーIDが見つかりません",
+		})
+	}
+
+	word := c.Param("word")
+	if word == "" {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "単語が指定されていません",
+		})
+	}
+
+	lang, ok := dictionary.ParseLang(c.QueryParam("lang"))
+	if !ok {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "言語の指定が不正です",
+		})
+	}
+	word = normalize.Word(word, lang.Source())
+
+	events, err := s.db.WordReviewEvents(userID, string(lang), word)
+	if err != nil {
+		log.Printf("Failed to fetch review events: %v", err)
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: "サーバーエラー",
+		})
+	}
+
+	response := []ReviewEventResponse{}
+
+	for _, event := range events {
+		response = append(response, ReviewEventResponse{
+			ReviewedAt:           event.ReviewedAt.String(),
+			Grade:                event.Grade,
+			ResponseTimeMs:       event.ResponseTimeMs,
+			PreviousIntervalDays: event.PreviousIntervalDays,
+			NewIntervalDays:      event.NewIntervalDays,
+			Device:               event.Device,
+		})
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+type SearchEventResponse struct {
+	SearchedAt  string `json:"searched_at"`
+	SurfaceForm string `json:"surface_form"`
+	SourceURL   string `json:"source_url"`
+	SourceTitle string `json:"source_title"`
+	Sentence    string `json:"sentence"`
+}
+
+// WordSearchesHandler handles GET /api/word/:word/searches - returns the search log of a word for the user
+func (s *Server) WordSearchesHandler(c echo.Context) error {
+	// Get user ID from context (set by auth middleware)
+	userID, ok := c.Get(string(auth.UserIDContextKey)).(string)
+	if !ok {
+		log.Println("User ID not found in context")
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: "ユーザーIDが見つかりません",
+		})
+	}
+
+	word := c.Param("word")
+	if word == "" {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "単語が指定されていません",
+		})
+	}
+
+	lang, ok := dictionary.ParseLang(c.QueryParam("lang"))
+	if !ok {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "言語の指定が不正です",
+		})
+	}
+	word = normalize.Word(word, lang.Source())
+
+	events, err := s.db.WordSearchEvents(userID, string(lang), word)
+	if err != nil {
+		log.Printf("Failed to fetch search events: %v", err)
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: "サーバーエラー",
+		})
+	}
+
+	response := []SearchEventResponse{}
+
+	for _, event := range events {
+		response = append(response, SearchEventResponse{
+			SearchedAt:  event.SearchedAt.String(),
+			SurfaceForm: event.SurfaceForm,
+			SourceURL:   event.SourceURL,
+			SourceTitle: event.SourceTitle,
+			Sentence:    event.Sentence,
+		})
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+type RelatedResponse struct {
+	Word      string        `json:"word"`
+	Synonyms  []RelatedWord `json:"synonyms"`
+	Antonyms  []RelatedWord `json:"antonyms"`
+	Hypernyms []RelatedWord `json:"hypernyms"`
+	// DerivedForms are derivationally related forms, e.g. "happiness" for "happy"
+	DerivedForms []RelatedWord `json:"derived_forms"`
+}
+
+type RelatedWord struct {
+	Word         string `json:"word"`
+	PartOfSpeech string `json:"part_of_speech"`
+	// Saved reports whether the word is already in the user's word list
+	Saved bool `json:"saved"`
+}
+
+// maxRelatedWords caps each relation of RelatedResponse
+const maxRelatedWords = 30
+
+// RelatedWordsHandler handles GET /api/word/:word/related - returns synonyms, antonyms, hypernyms
+// and derivationally related forms of a word from the imported WordNet
+func (s *Server) RelatedWordsHandler(c echo.Context) error {
+	// Get user ID from context (set by auth middleware)
+	userID, ok := c.Get(string(auth.UserIDContextKey)).(string)
+	if !ok {
+		log.Println("User ID not found in context")
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: "ユーザーIDが見つかりません",
+		})
+	}
+
+	word := c.Param("word")
+	if word == "" {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "単語が指定されていません",
+		})
+	}
+
+	lang, ok := dictionary.ParseLang(c.QueryParam("lang"))
+	if !ok {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "言語の指定が不正です",
+		})
+	}
+	word = normalize.Word(word, lang.Source())
+
+	groups := map[wordnet.Relation][]RelatedWord{}
+	// WordNet は英語のみなので、それ以外の言語では空の結果を返す
+	if lang.Source() == "en" {
+		relations, err := s.db.WordRelations(word)
+		if err != nil {
+			log.Printf("Failed to fetch word relations: %v", err)
+			return c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Message: "サーバーエラー",
+			})
+		}
+
+		seen := map[wordnet.Relation]map[string]bool{}
+		var words []string
+		for _, relation := range relations {
+			r := wordnet.Relation(relation.Relation)
+			if seen[r] == nil {
+				seen[r] = map[string]bool{}
+			}
+			// 複数のデータセットに同じ関係がある場合は1つにまとめる
+			if seen[r][relation.Related] {
+				continue
+			}
+			seen[r][relation.Related] = true
+			groups[r] = append(groups[r], RelatedWord{Word: relation.Related, PartOfSpeech: relation.PartOfSpeech})
+		}
+
+		for r, group := range groups {
+			// よく使われる語から並べる（頻度順位が不明な語は後ろ）
+			sort.SliceStable(group, func(i, j int) bool {
+				a, b := wordlist.FrequencyRank(group[i].Word), wordlist.FrequencyRank(group[j].Word)
+				return a != 0 && (b == 0 || a < b)
+			})
+			if len(group) > maxRelatedWords {
+				group = group[:maxRelatedWords]
+			}
+			groups[r] = group
+			for _, related := range group {
+				words = append(words, related.Word)
+			}
+		}
+
+		saved, err := s.db.SavedWords(userID, string(lang), words)
+		if err != nil {
+			log.Printf("Failed to fetch saved words: %v", err)
+			return c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Message: "サーバーエラー",
+			})
+		}
+		for _, group := range groups {
+			for i := range group {
+				group[i].Saved = saved[group[i].Word]
+			}
+		}
+	}
+
+	response := RelatedResponse{
+		Word:         word,
+		Synonyms:     append([]RelatedWord{}, groups[wordnet.Synonym]...),
+		Antonyms:     append([]RelatedWord{}, groups[wordnet.Antonym]...),
+		Hypernyms:    append([]RelatedWord{}, groups[wordnet.Hypernym]...),
+		DerivedForms: append([]RelatedWord{}, groups[wordnet.Derivation]...),
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+type ExamplesResponse struct {
+	Word     string               `json:"word"`
+	Examples []dictionary.Example `json:"examples"`
+}
+
+const (
+	defaultExampleLimit = 3
+	maxExampleLimit     = 10
+)
+
+// ExamplesHandler handles GET /api/word/:word/examples - returns short example sentences containing
+// the word or its inflections, with their translations, from the imported corpus
+func (s *Server) ExamplesHandler(c echo.Context) error {
+	word := c.Param("word")
+	if word == "" {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "単語が指定されていません",
+		})
+	}
+
+	lang, ok := dictionary.ParseLang(c.QueryParam("lang"))
+	if !ok {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "言語の指定が不正です",
+		})
+	}
+	word = normalize.Word(word, lang.Source())
+
+	limit := defaultExampleLimit
+	if value := c.QueryParam("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxExampleLimit {
+			return c.JSON(http.StatusBadRequest, ErrorResponse{
+				Message: fmt.Sprintf("limit は1から%dまでの整数で指定してください", maxExampleLimit),
+			})
+		}
+		limit = n
+	}
+
+	// 例文は原形で索引しているので、活用形を含む例文も見つかる
+	sentences, err := s.db.ExampleSentences(string(lang), corpus.Tokens(word, lang.Source()), limit)
+	if err != nil {
+		log.Printf("Failed to fetch example sentences: %v", err)
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: "サーバーエラー",
+		})
+	}
+
+	response := ExamplesResponse{
+		Word:     word,
+		Examples: []dictionary.Example{},
+	}
+	for _, sentence := range sentences {
+		response.Examples = append(response.Examples, dictionary.Example{
+			Text:        sentence.Text,
+			Translation: sentence.Translation,
+		})
 	}
 
-	// Return filtered response
 	return c.JSON(http.StatusOK, response)
 }
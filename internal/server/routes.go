@@ -34,10 +34,45 @@ func (s *Server) RegisterRoutes() http.Handler {
 	{
 		api.GET("/search", s.GetWordMeaningHandler)
 		api.POST("/search", s.SearchHandler)
+		api.GET("/search/suggest", s.SuggestHandler)
 		api.GET("/review/pending", s.GetPendingReviewsHandler)
 		api.PATCH("/review", s.ReviewHandler)
 		api.GET("/review/history", s.ReviewHistoryHandler)
+		api.POST("/review/sessions", s.CreateReviewSessionHandler)
+		api.GET("/review/sessions/current", s.CurrentReviewSessionHandler)
+		api.GET("/review/sessions/:id", s.GetReviewSessionHandler)
+		api.POST("/review/sessions/:id/answers", s.AnswerReviewSessionHandler)
+		api.POST("/review/sessions/:id/finish", s.FinishReviewSessionHandler)
 		api.GET("/word/:word", s.GetWordHandler)
+		api.PUT("/word/:word", s.UpdateWordNotesHandler)
+		api.PATCH("/word/:word", s.UpdateWordNotesHandler)
+		api.DELETE("/word/:word", s.DeleteWordHandler)
+		api.POST("/word/:word/restore", s.RestoreWordHandler)
+		api.PUT("/word/:word/archive", s.ArchiveWordHandler(true))
+		api.DELETE("/word/:word/archive", s.ArchiveWordHandler(false))
+		for path, flag := range map[string]wordFlag{"/suspend": suspendFlag, "/bury": buryFlag, "/star": starFlag} {
+			api.PUT("/word/:word"+path, s.WordFlagHandler(flag, true))
+			api.DELETE("/word/:word"+path, s.WordFlagHandler(flag, false))
+		}
+		api.GET("/word/:word/reviews", s.WordReviewsHandler)
+		api.GET("/word/:word/searches", s.WordSearchesHandler)
+		api.GET("/word/:word/related", s.RelatedWordsHandler)
+		api.GET("/word/:word/examples", s.ExamplesHandler)
+		api.GET("/trash", s.TrashHandler)
+		api.GET("/archive", s.ArchivedWordsHandler)
+		api.GET("/suspended", s.SuspendedWordsHandler)
+
+		// デッキとタグは同じハンドラーを使う
+		for path, kind := range map[string]collectionKind{"/decks": decks, "/tags": tags} {
+			api.GET(path, s.ListCollectionsHandler(kind))
+			api.POST(path, s.CreateCollectionHandler(kind))
+			api.GET(path+"/:id", s.GetCollectionHandler(kind))
+			api.PUT(path+"/:id", s.UpdateCollectionHandler(kind))
+			api.DELETE(path+"/:id", s.DeleteCollectionHandler(kind))
+			api.GET(path+"/:id/words", s.CollectionWordsHandler(kind))
+			api.PUT(path+"/:id/words/:word", s.AddCollectionWordHandler(kind))
+			api.DELETE(path+"/:id/words/:word", s.RemoveCollectionWordHandler(kind))
+		}
 	}
 
 	return e
@@ -2,26 +2,68 @@ package server
 
 import (
 	"fmt"
+	"log"
 	"net/http"
 	"os"
 	"strconv"
 	"time"
 
 	"tsumitan/internal/database"
+	"tsumitan/internal/dictionary"
+	"tsumitan/internal/pronounce"
+	"tsumitan/internal/scheduler"
+	"tsumitan/internal/suggest"
+	"tsumitan/internal/wordlist"
 )
 
 type Server struct {
 	port int
 
 	db database.Service
+
+	scheduler scheduler.Scheduler
+
+	dict dictionary.Provider
+
+	// pronunciations is the English pronouncing dictionary (bundled, or CMUdict from PRONUNCIATION_FILE)
+	pronunciations pronounce.Dictionary
+
+	// suggestions indexes the dictionary headwords of each direction
+	suggestions map[dictionary.Direction]*suggest.Index
 }
 
 func NewServer() *http.Server {
 	port, _ := strconv.Atoi(os.Getenv("PORT"))
+
+	sched, err := scheduler.FromEnv()
+	if err != nil {
+		log.Fatalf("failed to configure review scheduler: %v", err)
+	}
+
+	db := database.New()
+
+	dict, err := dictionary.FromEnv(db, db)
+	if err != nil {
+		log.Fatalf("failed to configure dictionary providers: %v", err)
+	}
+
+	pronunciations, err := pronounce.FromEnv()
+	if err != nil {
+		log.Fatalf("failed to load pronunciation dictionary: %v", err)
+	}
+
 	NewServer := &Server{
 		port: port,
 
-		db: database.New(),
+		db: db,
+
+		scheduler: sched,
+
+		dict: dict,
+
+		pronunciations: pronunciations,
+
+		suggestions: suggestionIndexes(db),
 	}
 
 	// Declare Server config
@@ -35,3 +77,56 @@ func NewServer() *http.Server {
 
 	return server
 }
+
+// suggestionIndexes builds the suggestion index of every direction in the offline dictionary.
+// 英和は辞書がインポートされていなくても同梱の単語リストから候補を返す
+func suggestionIndexes(db database.Service) map[dictionary.Direction]*suggest.Index {
+	indexes := map[dictionary.Direction]*suggest.Index{}
+
+	headwords, err := db.LexiconHeadwords()
+	if err != nil {
+		log.Printf("Failed to load dictionary headwords, using the bundled word list only: %v", err)
+		headwords = map[dictionary.Direction][]string{}
+	}
+	headwords[dictionary.EnJa] = append(headwords[dictionary.EnJa], wordlist.Words()...)
+
+	for dir, words := range headwords {
+		indexes[dir] = suggest.NewIndex(words)
+		log.Printf("Indexed %d %s headwords for suggestions", indexes[dir].Len(), dir)
+	}
+	return indexes
+}
+
+// pronunciation returns the pronunciation of the first of words found in the pronouncing dictionary.
+// 発音辞書は英語のみなので、学習中の言語が英語でない場合は空を返す
+func (s *Server) pronunciation(lang dictionary.Direction, words ...string) pronounce.Pronunciation {
+	if lang.Source() != "en" {
+		return pronounce.Pronunciation{}
+	}
+	for _, word := range words {
+		if p, ok := s.pronunciations.Lookup(word); ok {
+			return p
+		}
+	}
+	return pronounce.Pronunciation{}
+}
+
+// reviewScheduler returns the scheduler used for userID's reviews.
+// ユーザーごとに最適化した FSRS パラメータがあれば、REVIEW_SCHEDULER によらずそれを使う
+func (s *Server) reviewScheduler(userID string) scheduler.Scheduler {
+	params, err := s.db.GetFSRSParameters(userID)
+	if err != nil {
+		log.Printf("Failed to fetch FSRS parameters for user %s, using defaults: %v", userID, err)
+		return s.scheduler
+	}
+	if params == nil {
+		return s.scheduler
+	}
+
+	weights, err := scheduler.FSRSWeightsFromSlice(params.Weights)
+	if err != nil {
+		log.Printf("Invalid FSRS parameters for user %s, using defaults: %v", userID, err)
+		return s.scheduler
+	}
+	return scheduler.NewFSRS(weights)
+}
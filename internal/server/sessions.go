@@ -0,0 +1,375 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"tsumitan/internal/auth"
+	"tsumitan/internal/database"
+	"tsumitan/internal/scheduler"
+
+	"github.com/labstack/echo/v4"
+)
+
+// 1回のセッションで出題する単語の数
+const (
+	defaultSessionSize = 20
+	maxSessionSize     = 100
+)
+
+// nextDueWindow is how far ahead the summary of a session counts the words coming due
+const nextDueWindow = 24 * time.Hour
+
+type ReviewSessionResponse struct {
+	ID         uint   `json:"id"`
+	Lang       string `json:"lang"`
+	StartedAt  string `json:"started_at"`
+	FinishedAt string `json:"finished_at,omitempty"`
+	Total      int    `json:"total"`
+	Answered   int    `json:"answered"`
+	// Cards are in the order they are asked; answered cards are kept so the session can be resumed
+	Cards []SessionCardResponse `json:"cards"`
+}
+
+type SessionCardResponse struct {
+	Word  string `json:"word"`
+	Lang  string `json:"lang"`
+	Kind  string `json:"kind"`
+	Level string `json:"level,omitempty"`
+	// Notes, Mnemonic and CustomDefinition are written by the learner
+	Notes            string `json:"notes"`
+	Mnemonic         string `json:"mnemonic"`
+	CustomDefinition string `json:"custom_definition"`
+	// New is true for words reviewed for the first time in this session
+	New            bool   `json:"new"`
+	Answered       bool   `json:"answered"`
+	Grade          string `json:"grade,omitempty"`
+	ResponseTimeMs int    `json:"response_time_ms,omitempty"`
+}
+
+type ReviewSessionSummaryResponse struct {
+	ID         uint   `json:"id"`
+	Lang       string `json:"lang"`
+	StartedAt  string `json:"started_at"`
+	FinishedAt string `json:"finished_at"`
+	Total      int    `json:"total"`
+	Answered   int    `json:"answered"`
+	// Correct is the number of answers other than "again"; Accuracy is Correct / Answered
+	Correct  int     `json:"correct"`
+	Accuracy float64 `json:"accuracy"`
+	// TimeSpentMs is the total response time of the answers
+	TimeSpentMs int `json:"time_spent_ms"`
+	// NewWords are the words reviewed for the first time and not answered "again"
+	NewWords []string `json:"new_words"`
+	// NextDueCount is the number of words coming due within the next 24 hours, not counting overdue words
+	NextDueCount int `json:"next_due_count"`
+}
+
+func reviewSessionResponse(info *database.ReviewSessionInfo) ReviewSessionResponse {
+	response := ReviewSessionResponse{
+		ID:        info.ID,
+		Lang:      info.Lang,
+		StartedAt: info.StartedAt.String(),
+		Total:     len(info.Cards),
+		Cards:     []SessionCardResponse{},
+	}
+	if info.FinishedAt != nil {
+		response.FinishedAt = info.FinishedAt.String()
+	}
+
+	for _, card := range info.Cards {
+		answered := card.AnsweredAt != nil
+		if answered {
+			response.Answered++
+		}
+		response.Cards = append(response.Cards, SessionCardResponse{
+			Word:             card.Word,
+			Lang:             card.Lang,
+			Kind:             card.Kind,
+			Level:            card.Level,
+			Notes:            card.Notes,
+			Mnemonic:         card.Mnemonic,
+			CustomDefinition: card.CustomDefinition,
+			New:              card.FirstReview,
+			Answered:         answered,
+			Grade:            card.Grade,
+			ResponseTimeMs:   card.ResponseTimeMs,
+		})
+	}
+
+	return response
+}
+
+// CreateReviewSessionHandler handles POST /api/review/sessions - starts a review session with a batch
+// of due words chosen from the review queue. It takes the query parameters of GET /api/review/pending and size
+func (s *Server) CreateReviewSessionHandler(c echo.Context) error {
+	// Get user ID from context (set by auth middleware)
+	userID, ok := c.Get(string(auth.UserIDContextKey)).(string)
+	if !ok {
+		log.Println("User ID not found in context")
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: "ユーザーIDが見つかりません",
+		})
+	}
+
+	lang, ok := langFilter(c)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "言語の指定が不正です",
+		})
+	}
+
+	opts, ok := pendingOptions(c)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "レベル・スター・並び順の指定が不正です",
+		})
+	}
+
+	opts.WordFilter, ok = wordFilter(c)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "デッキまたはタグの指定が不正です",
+		})
+	}
+
+	size := defaultSessionSize
+	if value := c.QueryParam("size"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxSessionSize {
+			return c.JSON(http.StatusBadRequest, ErrorResponse{
+				Message: fmt.Sprintf("size は1から%dまでの整数で指定してください", maxSessionSize),
+			})
+		}
+		size = n
+	}
+
+	session, err := s.db.CreateReviewSession(userID, lang, time.Now(), opts, size)
+	if err != nil {
+		log.Printf("Failed to create review session: %v", err)
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: "サーバーエラー",
+		})
+	}
+	if session == nil {
+		return c.JSON(http.StatusNotFound, ErrorResponse{
+			Message: "復習期限を迎えた単語がありません",
+		})
+	}
+
+	log.Printf("Review session %d started for user %s with %d words", session.ID, userID, len(session.Cards))
+
+	return c.JSON(http.StatusCreated, reviewSessionResponse(session))
+}
+
+// CurrentReviewSessionHandler handles GET /api/review/sessions/current - returns the most recently
+// started session that has not been finished, to resume an interrupted session
+func (s *Server) CurrentReviewSessionHandler(c echo.Context) error {
+	// Get user ID from context (set by auth middleware)
+	userID, ok := c.Get(string(auth.UserIDContextKey)).(string)
+	if !ok {
+		log.Println("User ID not found in context")
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: "ユーザーIDが見つかりません",
+		})
+	}
+
+	session, err := s.db.CurrentReviewSession(userID)
+	if err != nil {
+		log.Printf("Failed to fetch current review session: %v", err)
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: "サーバーエラー",
+		})
+	}
+	if session == nil {
+		return c.JSON(http.StatusNotFound, ErrorResponse{
+			Message: "再開できるセッションがありません",
+		})
+	}
+
+	return c.JSON(http.StatusOK, reviewSessionResponse(session))
+}
+
+// GetReviewSessionHandler handles GET /api/review/sessions/:id - returns a session with its cards and answers
+func (s *Server) GetReviewSessionHandler(c echo.Context) error {
+	// Get user ID from context (set by auth middleware)
+	userID, ok := c.Get(string(auth.UserIDContextKey)).(string)
+	if !ok {
+		log.Println("User ID not found in context")
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: "ユーザーIDが見つかりません",
+		})
+	}
+
+	id, ok := pathID(c)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "IDが不正です",
+		})
+	}
+
+	session, err := s.db.GetReviewSession(userID, id)
+	if err != nil {
+		log.Printf("Failed to fetch review session: %v", err)
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: "サーバーエラー",
+		})
+	}
+	if session == nil {
+		return c.JSON(http.StatusNotFound, ErrorResponse{
+			Message: "セッションが見つかりません",
+		})
+	}
+
+	return c.JSON(http.StatusOK, reviewSessionResponse(session))
+}
+
+// AnswerReviewSessionHandler handles POST /api/review/sessions/:id/answers - records the answer to a card
+// of the session. The word is rescheduled and logged as with PATCH /api/review
+func (s *Server) AnswerReviewSessionHandler(c echo.Context) error {
+	// Get user ID from context (set by auth middleware)
+	userID, ok := c.Get(string(auth.UserIDContextKey)).(string)
+	if !ok {
+		log.Println("User ID not found in context")
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: "ユーザーIDが見つかりません",
+		})
+	}
+
+	id, ok := pathID(c)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "IDが不正です",
+		})
+	}
+
+	var req ReviewRequest
+	if err := c.Bind(&req); err != nil {
+		log.Printf("Failed to bind request: %v", err)
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "リクエスト不備",
+		})
+	}
+
+	lang, word, review, message := reviewInput(c, req)
+	if message != "" {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: message,
+		})
+	}
+
+	err := s.db.AnswerReviewSession(userID, id, string(lang), word, s.reviewScheduler(userID), review)
+	switch {
+	case errors.Is(err, database.ErrSessionNotFound):
+		return c.JSON(http.StatusNotFound, ErrorResponse{
+			Message: "セッションが見つかりません",
+		})
+	case errors.Is(err, database.ErrCardNotFound):
+		return c.JSON(http.StatusNotFound, ErrorResponse{
+			Message: "セッションに含まれない単語です",
+		})
+	case errors.Is(err, database.ErrSessionFinished):
+		return c.JSON(http.StatusConflict, ErrorResponse{
+			Message: "セッションは終了しています",
+		})
+	case errors.Is(err, database.ErrCardAnswered):
+		return c.JSON(http.StatusConflict, ErrorResponse{
+			Message: "この単語は回答済みです",
+		})
+	case err != nil:
+		log.Printf("Failed to answer review session: %v", err)
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: "サーバーエラー",
+		})
+	}
+
+	log.Printf("Review session %d answered for user %s, word: %s, grade: %s", id, userID, word, review.Grade)
+
+	session, err := s.db.GetReviewSession(userID, id)
+	if err != nil || session == nil {
+		log.Printf("Failed to fetch review session: %v", err)
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: "サーバーエラー",
+		})
+	}
+
+	return c.JSON(http.StatusOK, reviewSessionResponse(session))
+}
+
+// FinishReviewSessionHandler handles POST /api/review/sessions/:id/finish - finishes a session and returns
+// its summary. Unanswered cards are left as they are; finishing a session again returns the same summary
+func (s *Server) FinishReviewSessionHandler(c echo.Context) error {
+	// Get user ID from context (set by auth middleware)
+	userID, ok := c.Get(string(auth.UserIDContextKey)).(string)
+	if !ok {
+		log.Println("User ID not found in context")
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: "ユーザーIDが見つかりません",
+		})
+	}
+
+	id, ok := pathID(c)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "IDが不正です",
+		})
+	}
+
+	now := time.Now()
+	session, err := s.db.FinishReviewSession(userID, id, now)
+	if err != nil {
+		log.Printf("Failed to finish review session: %v", err)
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: "サーバーエラー",
+		})
+	}
+	if session == nil {
+		return c.JSON(http.StatusNotFound, ErrorResponse{
+			Message: "セッションが見つかりません",
+		})
+	}
+
+	// 次の24時間で新たに復習期限を迎える単語の数（セッションと同じ言語ペア）
+	nextDue, err := s.db.CountDueWords(userID, session.Lang, now, now.Add(nextDueWindow))
+	if err != nil {
+		log.Printf("Failed to count due words: %v", err)
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: "サーバーエラー",
+		})
+	}
+
+	response := ReviewSessionSummaryResponse{
+		ID:           session.ID,
+		Lang:         session.Lang,
+		StartedAt:    session.StartedAt.String(),
+		FinishedAt:   session.FinishedAt.String(),
+		Total:        len(session.Cards),
+		NewWords:     []string{},
+		NextDueCount: int(nextDue),
+	}
+	for _, card := range session.Cards {
+		if card.AnsweredAt == nil {
+			continue
+		}
+		response.Answered++
+		response.TimeSpentMs += card.ResponseTimeMs
+		if card.Grade == scheduler.GradeAgain.String() {
+			continue
+		}
+		response.Correct++
+		if card.FirstReview {
+			response.NewWords = append(response.NewWords, card.Word)
+		}
+	}
+	if response.Answered > 0 {
+		response.Accuracy = float64(response.Correct) / float64(response.Answered)
+	}
+
+	log.Printf("Review session %d finished for user %s: %d/%d answered", id, userID, response.Answered, response.Total)
+
+	return c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,389 @@
+package server
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"tsumitan/internal/auth"
+	"tsumitan/internal/database"
+	"tsumitan/internal/dictionary"
+	"tsumitan/internal/normalize"
+
+	"github.com/labstack/echo/v4"
+)
+
+type TrashedWordResponse struct {
+	Word        string `json:"word"`
+	Lang        string `json:"lang"`
+	Kind        string `json:"kind"`
+	SearchCount int    `json:"search_count"`
+	ReviewCount int    `json:"review_count"`
+	DeletedAt   string `json:"deleted_at"`
+}
+
+type ArchivedWordResponse struct {
+	Word         string `json:"word"`
+	Lang         string `json:"lang"`
+	Kind         string `json:"kind"`
+	Level        string `json:"level,omitempty"`
+	SearchCount  int    `json:"search_count"`
+	ReviewCount  int    `json:"review_count"`
+	LapseCount   int    `json:"lapse_count"`
+	LastReviewed string `json:"last_reviewed"`
+	ArchivedAt   string `json:"archived_at"`
+}
+
+// DeleteWordHandler handles DELETE /api/word/:word - moves a word to the trash.
+// ゴミ箱の単語は cmd/purge-trash が完全に削除するまで POST /api/word/:word/restore で戻せる
+func (s *Server) DeleteWordHandler(c echo.Context) error {
+	// Get user ID from context (set by auth middleware)
+	userID, ok := c.Get(string(auth.UserIDContextKey)).(string)
+	if !ok {
+		log.Println("User ID not found in context")
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: "ユーザーIDが見つかりません",
+		})
+	}
+
+	word := c.Param("word")
+	if word == "" {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "単語が指定されていません",
+		})
+	}
+
+	lang, ok := dictionary.ParseLang(c.QueryParam("lang"))
+	if !ok {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "言語の指定が不正です",
+		})
+	}
+	word = normalize.Word(word, lang.Source())
+
+	deleted, err := s.db.DeleteWord(userID, string(lang), word)
+	if err != nil {
+		log.Printf("Failed to delete word: %v", err)
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: "サーバーエラー",
+		})
+	}
+	if !deleted {
+		return c.JSON(http.StatusNotFound, ErrorResponse{
+			Message: "単語が見つかりません",
+		})
+	}
+
+	log.Printf("Word moved to trash for user %s, word: %s", userID, word)
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": word + "をゴミ箱に移しました"})
+}
+
+// RestoreWordHandler handles POST /api/word/:word/restore - takes a word out of the trash
+func (s *Server) RestoreWordHandler(c echo.Context) error {
+	// Get user ID from context (set by auth middleware)
+	userID, ok := c.Get(string(auth.UserIDContextKey)).(string)
+	if !ok {
+		log.Println("User ID not found in context")
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: "ユーザーIDが見つかりません",
+		})
+	}
+
+	word := c.Param("word")
+	if word == "" {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "単語が指定されていません",
+		})
+	}
+
+	lang, ok := dictionary.ParseLang(c.QueryParam("lang"))
+	if !ok {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "言語の指定が不正です",
+		})
+	}
+	word = normalize.Word(word, lang.Source())
+
+	wordRecord, err := s.db.RestoreWord(userID, string(lang), word)
+	if err != nil {
+		log.Printf("Failed to restore word: %v", err)
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: "サーバーエラー",
+		})
+	}
+	if wordRecord == nil {
+		return c.JSON(http.StatusNotFound, ErrorResponse{
+			Message: "ゴミ箱に単語が見つかりません",
+		})
+	}
+
+	log.Printf("Word restored for user %s, word: %s", userID, word)
+
+	return c.JSON(http.StatusOK, s.wordDetail(wordRecord))
+}
+
+// TrashHandler handles GET /api/trash - returns the words in the trash, most recently deleted first
+func (s *Server) TrashHandler(c echo.Context) error {
+	// Get user ID from context (set by auth middleware)
+	userID, ok := c.Get(string(auth.UserIDContextKey)).(string)
+	if !ok {
+		log.Println("User ID not found in context")
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: "ユーザーIDが見つかりません",
+		})
+	}
+
+	lang, ok := langFilter(c)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "言語の指定が不正です",
+		})
+	}
+
+	words, err := s.db.TrashedWords(userID, lang)
+	if err != nil {
+		log.Printf("Failed to fetch trash: %v", err)
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: "サーバーエラー",
+		})
+	}
+
+	response := []TrashedWordResponse{}
+	for _, w := range words {
+		response = append(response, TrashedWordResponse{
+			Word:        w.Word,
+			Lang:        w.Lang,
+			Kind:        w.Kind,
+			SearchCount: w.SearchCount,
+			ReviewCount: w.ReviewCount,
+			DeletedAt:   w.DeletedAt.Time.String(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// ArchiveWordHandler handles PUT /api/word/:word/archive (archived is true) and DELETE /api/word/:word/archive -
+// marks a word as mastered, taking it out of the review queue while keeping it in the history, or brings it back
+func (s *Server) ArchiveWordHandler(archived bool) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// Get user ID from context (set by auth middleware)
+		userID, ok := c.Get(string(auth.UserIDContextKey)).(string)
+		if !ok {
+			log.Println("User ID not found in context")
+			return c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Message: "ユーザーIDが見つかりません",
+			})
+		}
+
+		word := c.Param("word")
+		if word == "" {
+			return c.JSON(http.StatusBadRequest, ErrorResponse{
+				Message: "単語が指定されていません",
+			})
+		}
+
+		lang, ok := dictionary.ParseLang(c.QueryParam("lang"))
+		if !ok {
+			return c.JSON(http.StatusBadRequest, ErrorResponse{
+				Message: "言語の指定が不正です",
+			})
+		}
+		word = normalize.Word(word, lang.Source())
+
+		wordRecord, err := s.db.SetWordArchived(userID, string(lang), word, archived, time.Now())
+		if err != nil {
+			log.Printf("Failed to archive word: %v", err)
+			return c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Message: "サーバーエラー",
+			})
+		}
+		if wordRecord == nil {
+			return c.JSON(http.StatusNotFound, ErrorResponse{
+				Message: "単語が見つかりません",
+			})
+		}
+
+		log.Printf("Word archived=%t for user %s, word: %s", archived, userID, word)
+
+		return c.JSON(http.StatusOK, s.wordDetail(wordRecord))
+	}
+}
+
+// wordFlag is a review queue flag toggled by its own endpoint.
+type wordFlag struct {
+	flag database.WordFlag
+	// value returns the value of the flag column that turns the flag on or off,
+	// or false if the request is invalid
+	value func(c echo.Context, on bool, now time.Time) (any, bool)
+}
+
+var (
+	suspendFlag = wordFlag{flag: database.FlagSuspended, value: boolFlag}
+	buryFlag    = wordFlag{flag: database.FlagBuriedUntil, value: buriedUntil}
+	starFlag    = wordFlag{flag: database.FlagStarred, value: boolFlag}
+)
+
+func boolFlag(c echo.Context, on bool, now time.Time) (any, bool) {
+	return on, true
+}
+
+// buriedUntil returns the next midnight in the time zone of the tz query parameter
+// (an IANA name such as "Asia/Tokyo", UTC if omitted) when burying a word.
+func buriedUntil(c echo.Context, on bool, now time.Time) (any, bool) {
+	if !on {
+		return nil, true
+	}
+
+	loc := time.UTC
+	if tz := c.QueryParam("tz"); tz != "" {
+		var err error
+		if loc, err = time.LoadLocation(tz); err != nil {
+			return nil, false
+		}
+	}
+	year, month, day := now.In(loc).Date()
+	return time.Date(year, month, day+1, 0, 0, 0, 0, loc), true
+}
+
+// WordFlagHandler handles PUT (on is true) and DELETE on /api/word/:word/suspend, /bury and /star -
+// suspends a word until it is unsuspended, buries it until tomorrow or stars it to review it first
+func (s *Server) WordFlagHandler(flag wordFlag, on bool) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// Get user ID from context (set by auth middleware)
+		userID, ok := c.Get(string(auth.UserIDContextKey)).(string)
+		if !ok {
+			log.Println("User ID not found in context")
+			return c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Message: "ユーザーIDが見つかりません",
+			})
+		}
+
+		word := c.Param("word")
+		if word == "" {
+			return c.JSON(http.StatusBadRequest, ErrorResponse{
+				Message: "単語が指定されていません",
+			})
+		}
+
+		lang, ok := dictionary.ParseLang(c.QueryParam("lang"))
+		if !ok {
+			return c.JSON(http.StatusBadRequest, ErrorResponse{
+				Message: "言語の指定が不正です",
+			})
+		}
+		word = normalize.Word(word, lang.Source())
+
+		value, ok := flag.value(c, on, time.Now())
+		if !ok {
+			return c.JSON(http.StatusBadRequest, ErrorResponse{
+				Message: "タイムゾーンの指定が不正です",
+			})
+		}
+
+		wordRecord, err := s.db.SetWordFlag(userID, string(lang), word, flag.flag, value)
+		if err != nil {
+			log.Printf("Failed to set %s: %v", flag.flag, err)
+			return c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Message: "サーバーエラー",
+			})
+		}
+		if wordRecord == nil {
+			return c.JSON(http.StatusNotFound, ErrorResponse{
+				Message: "単語が見つかりません",
+			})
+		}
+
+		log.Printf("Word %s=%v for user %s, word: %s", flag.flag, value, userID, word)
+
+		return c.JSON(http.StatusOK, s.wordDetail(wordRecord))
+	}
+}
+
+// SuspendedWordsHandler handles GET /api/suspended - returns the suspended words, ordered by word
+func (s *Server) SuspendedWordsHandler(c echo.Context) error {
+	// Get user ID from context (set by auth middleware)
+	userID, ok := c.Get(string(auth.UserIDContextKey)).(string)
+	if !ok {
+		log.Println("User ID not found in context")
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: "ユーザーIDが見つかりません",
+		})
+	}
+
+	lang, ok := langFilter(c)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "言語の指定が不正です",
+		})
+	}
+
+	words, err := s.db.SuspendedWords(userID, lang)
+	if err != nil {
+		log.Printf("Failed to fetch suspended words: %v", err)
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: "サーバーエラー",
+		})
+	}
+
+	response := []CollectionWordResponse{}
+	for _, w := range words {
+		response = append(response, CollectionWordResponse{
+			Word:        w.Word,
+			Lang:        w.Lang,
+			Kind:        w.Kind,
+			Level:       w.Level,
+			SearchCount: w.SearchCount,
+			ReviewCount: w.ReviewCount,
+			DueAt:       w.DueAt.String(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// ArchivedWordsHandler handles GET /api/archive - returns the archived words, most recently archived first
+func (s *Server) ArchivedWordsHandler(c echo.Context) error {
+	// Get user ID from context (set by auth middleware)
+	userID, ok := c.Get(string(auth.UserIDContextKey)).(string)
+	if !ok {
+		log.Println("User ID not found in context")
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: "ユーザーIDが見つかりません",
+		})
+	}
+
+	lang, ok := langFilter(c)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "言語の指定が不正です",
+		})
+	}
+
+	words, err := s.db.ArchivedWords(userID, lang)
+	if err != nil {
+		log.Printf("Failed to fetch archived words: %v", err)
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: "サーバーエラー",
+		})
+	}
+
+	response := []ArchivedWordResponse{}
+	for _, w := range words {
+		response = append(response, ArchivedWordResponse{
+			Word:         w.Word,
+			Lang:         w.Lang,
+			Kind:         w.Kind,
+			Level:        w.Level,
+			SearchCount:  w.SearchCount,
+			ReviewCount:  w.ReviewCount,
+			LapseCount:   w.LapseCount,
+			LastReviewed: w.LastReviewed.String(),
+			ArchivedAt:   w.ArchivedAt.String(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,93 @@
+// Package suggest finds dictionary headwords for what the user is typing:
+// prefix completions for the search box and spelling corrections for words
+// that were not found.
+package suggest
+
+import (
+	"sort"
+	"strings"
+
+	"tsumitan/internal/wordlist"
+)
+
+// Index is an in-memory index of the headwords of one lookup direction,
+// built once when the server starts. It is safe for concurrent use.
+type Index struct {
+	// words is sorted and has no duplicates
+	words []string
+	// ranks holds the frequency rank of each word, 0 if unknown
+	ranks []int
+}
+
+// NewIndex builds an index of headwords.
+func NewIndex(headwords []string) *Index {
+	words := make([]string, len(headwords))
+	copy(words, headwords)
+	sort.Strings(words)
+
+	// 重複を取り除く
+	unique := words[:0]
+	for i, word := range words {
+		if word == "" || (i > 0 && word == words[i-1]) {
+			continue
+		}
+		unique = append(unique, word)
+	}
+
+	ranks := make([]int, len(unique))
+	for i, word := range unique {
+		ranks[i] = wordlist.FrequencyRank(word)
+	}
+
+	return &Index{words: unique, ranks: ranks}
+}
+
+// Len returns the number of headwords in the index.
+func (i *Index) Len() int {
+	return len(i.words)
+}
+
+// Prefix returns up to limit headwords starting with prefix, most frequent first.
+func (i *Index) Prefix(prefix string, limit int) []string {
+	if prefix == "" || limit <= 0 {
+		return []string{}
+	}
+
+	// ソート済みなので前方一致する語は連続している
+	start := sort.SearchStrings(i.words, prefix)
+	end := start
+	for end < len(i.words) && strings.HasPrefix(i.words[end], prefix) {
+		end++
+	}
+
+	matches := make([]int, 0, end-start)
+	for j := start; j < end; j++ {
+		matches = append(matches, j)
+	}
+	sort.SliceStable(matches, func(a, b int) bool {
+		return i.less(matches[a], matches[b])
+	})
+
+	completions := []string{}
+	for _, j := range matches {
+		if len(completions) == limit {
+			break
+		}
+		completions = append(completions, i.words[j])
+	}
+	return completions
+}
+
+// less orders words by frequency rank, putting words without a rank last
+// and shorter words first among them.
+func (i *Index) less(a, b int) bool {
+	ra, rb := i.ranks[a], i.ranks[b]
+	switch {
+	case ra != 0 && rb != 0:
+		return ra < rb
+	case ra != 0 || rb != 0:
+		return ra != 0
+	default:
+		return len(i.words[a]) < len(i.words[b])
+	}
+}
@@ -0,0 +1,41 @@
+package suggest
+
+import "math"
+
+// qwertyRows are the letter rows of a QWERTY keyboard and how far each row is shifted right.
+var qwertyRows = []struct {
+	keys   string
+	offset float64
+}{
+	{"qwertyuiop", 0},
+	{"asdfghjkl", 0.25},
+	{"zxcvbnm", 0.75},
+}
+
+type keyPosition struct {
+	x, y float64
+}
+
+var keyPositions = func() map[rune]keyPosition {
+	positions := map[rune]keyPosition{}
+	for y, row := range qwertyRows {
+		for x, key := range row.keys {
+			positions[key] = keyPosition{x: float64(x) + row.offset, y: float64(y)}
+		}
+	}
+	return positions
+}()
+
+// adjacentKeys reports whether a and b are next to each other on a QWERTY keyboard,
+// including the keys diagonally above and below.
+func adjacentKeys(a, b rune) bool {
+	pa, ok := keyPositions[a]
+	if !ok {
+		return false
+	}
+	pb, ok := keyPositions[b]
+	if !ok {
+		return false
+	}
+	return math.Hypot(pa.x-pb.x, pa.y-pb.y) <= 1.3
+}
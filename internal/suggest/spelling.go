@@ -0,0 +1,101 @@
+package suggest
+
+import (
+	"math"
+	"sort"
+)
+
+// MaxDistance is the largest edit distance at which a headword is suggested as a correction.
+const MaxDistance = 2.0
+
+// adjacentCost is the cost of substituting a key with one next to it on the keyboard,
+// which is a more likely typo than any other substitution.
+const adjacentCost = 0.5
+
+// transposeCost is the cost of swapping two neighbouring letters ("teh" for "the"),
+// which is as common a typo as hitting an adjacent key.
+const transposeCost = 0.5
+
+// Corrections returns up to limit headwords that word is likely a misspelling of,
+// e.g. "receive" for "recieve", closest first and then most frequent first.
+// 編集距離（隣接する文字の入れ替えを含む）で比べ、キーボード上で隣のキーへの打ち間違いは軽く数える
+func (i *Index) Corrections(word string, limit int) []string {
+	target := []rune(word)
+	// 短い単語は1文字違いまでにする
+	maxDistance := MaxDistance
+	if len(target) <= 4 {
+		maxDistance = 1
+	}
+
+	type candidate struct {
+		index    int
+		distance float64
+	}
+	var candidates []candidate
+
+	for j, headword := range i.words {
+		runes := []rune(headword)
+		if math.Abs(float64(len(runes)-len(target))) > maxDistance {
+			continue
+		}
+		d := distance(target, runes, maxDistance)
+		if d == 0 || d > maxDistance {
+			continue
+		}
+		candidates = append(candidates, candidate{index: j, distance: d})
+	}
+
+	sort.SliceStable(candidates, func(a, b int) bool {
+		if candidates[a].distance != candidates[b].distance {
+			return candidates[a].distance < candidates[b].distance
+		}
+		return i.less(candidates[a].index, candidates[b].index)
+	})
+
+	suggestions := []string{}
+	for _, c := range candidates {
+		if len(suggestions) == limit {
+			break
+		}
+		suggestions = append(suggestions, i.words[c.index])
+	}
+	return suggestions
+}
+
+// distance returns the optimal string alignment distance between a and b, weighting
+// substitutions of adjacent keys by adjacentCost and transpositions by transposeCost. It gives up and returns a value
+// greater than limit as soon as the distance is known to exceed limit.
+func distance(a, b []rune, limit float64) float64 {
+	prev2 := make([]float64, len(b)+1)
+	prev := make([]float64, len(b)+1)
+	curr := make([]float64, len(b)+1)
+	for j := range prev {
+		prev[j] = float64(j)
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = float64(i)
+		rowMin := curr[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 0.0
+			if a[i-1] != b[j-1] {
+				cost = 1
+				if adjacentKeys(a[i-1], b[j-1]) {
+					cost = adjacentCost
+				}
+			}
+			d := min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			// recieve → receive のような隣り合う文字の入れ替え
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d = min(d, prev2[j-2]+transposeCost)
+			}
+			curr[j] = d
+			rowMin = min(rowMin, d)
+		}
+		if rowMin > limit {
+			return rowMin
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,88 @@
+package suggest
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		limit float64
+		want  float64
+	}{
+		{"cat", "cat", MaxDistance, 0},
+		{"", "", MaxDistance, 0},
+		{"", "abc", 10, 3},
+		// 挿入・削除・置換
+		{"cat", "cats", MaxDistance, 1},
+		{"cat", "at", MaxDistance, 1},
+		{"cat", "cut", MaxDistance, 1},
+		{"café", "cafe", MaxDistance, 1},
+		{"kitten", "sitting", 10, 3},
+		// 隣のキーへの打ち間違い
+		{"cat", "car", MaxDistance, 0.5},
+		{"cat", "dog", MaxDistance, 2},
+		// 隣り合う文字の入れ替え
+		{"recieve", "receive", MaxDistance, 0.5},
+		{"teh", "the", MaxDistance, 0.5},
+		// 入れ替えた文字はそれ以上編集しない（制限付きの編集距離）
+		{"abc", "ca", 10, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			if got := distance([]rune(tt.a), []rune(tt.b), tt.limit); got != tt.want {
+				t.Errorf("distance(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+			if got := distance([]rune(tt.b), []rune(tt.a), tt.limit); got != tt.want {
+				t.Errorf("distance(%q, %q) = %v, want %v", tt.b, tt.a, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDistanceGivesUpOverLimit(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		limit float64
+	}{
+		{"kitten", "sitting", 1},
+		{"abc", "xyz", 2},
+		{"receive", "deceived", 0.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			if got := distance([]rune(tt.a), []rune(tt.b), tt.limit); got <= tt.limit {
+				t.Errorf("distance(%q, %q, %v) = %v, want more than the limit", tt.a, tt.b, tt.limit, got)
+			}
+		})
+	}
+}
+
+func TestCorrections(t *testing.T) {
+	index := NewIndex([]string{"the", "tea", "ten", "thee", "receive", "relieve", "believe"})
+
+	tests := []struct {
+		word  string
+		limit int
+		want  []string
+	}{
+		// 同じ距離なら頻度の高い語を先にする
+		{"teh", 5, []string{"the", "ten", "tea"}},
+		{"teh", 1, []string{"the"}},
+		{"recieve", 5, []string{"receive", "relieve", "believe"}},
+		// 4文字以下の語は1文字違いまで
+		{"the", 5, []string{"thee"}},
+		{"xyz", 5, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			if got := index.Corrections(tt.word, tt.limit); !slices.Equal(got, tt.want) {
+				t.Errorf("Corrections(%q, %d) = %q, want %q", tt.word, tt.limit, got, tt.want)
+			}
+		})
+	}
+}
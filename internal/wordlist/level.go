@@ -0,0 +1,39 @@
+package wordlist
+
+import "strings"
+
+// Levels are the CEFR levels from easiest to hardest.
+var Levels = []string{"A1", "A2", "B1", "B2", "C1", "C2"}
+
+// levelBands are the lowest frequency rank outside each level up to C1.
+// 頻度の高い語ほど早い段階で学ぶという前提で、頻度順位から CEFR レベルを推定する
+var levelBands = []int{500, 1000, 1700, 2500}
+
+// ValidLevel reports whether level is one of Levels.
+func ValidLevel(level string) bool {
+	for _, l := range Levels {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
+
+// Level estimates the CEFR level of an English headword from its frequency rank.
+// Words missing from the bundled list and phrases are not estimated and return "".
+func Level(word string) string {
+	if word == "" || strings.Contains(word, " ") {
+		return ""
+	}
+
+	rank := FrequencyRank(word)
+	if rank == 0 {
+		return ""
+	}
+	for i, band := range levelBands {
+		if rank <= band {
+			return Levels[i]
+		}
+	}
+	return "C1"
+}
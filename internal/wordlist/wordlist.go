@@ -0,0 +1,48 @@
+// Package wordlist provides the English word lists bundled with the server.
+package wordlist
+
+import (
+	_ "embed"
+	"strings"
+)
+
+//go:embed frequency.txt
+var frequencyFile string
+
+// frequencyWords holds the bundled words, most frequent first
+var frequencyWords = parseList(frequencyFile)
+
+// frequencyRanks maps each bundled word onto its 1-based frequency rank
+var frequencyRanks = func() map[string]int {
+	ranks := make(map[string]int, len(frequencyWords))
+	for i, word := range frequencyWords {
+		ranks[word] = i + 1
+	}
+	return ranks
+}()
+
+// FrequencyRank returns the corpus frequency rank of an English headword
+// (1 for the most frequent word), or 0 if the word is not in the bundled list.
+func FrequencyRank(word string) int {
+	return frequencyRanks[word]
+}
+
+// Words returns the bundled English words, most frequent first.
+func Words() []string {
+	words := make([]string, len(frequencyWords))
+	copy(words, frequencyWords)
+	return words
+}
+
+// parseList reads one word per line, skipping blank lines and # comments.
+func parseList(data string) []string {
+	var words []string
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, line)
+	}
+	return words
+}
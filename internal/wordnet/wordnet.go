@@ -0,0 +1,246 @@
+// Package wordnet reads the lexical relations between English words from the
+// Princeton WordNet database files (WNDB format: data.noun, data.verb, data.adj, data.adv).
+package wordnet
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"tsumitan/internal/normalize"
+)
+
+// Relation is the kind of relation between two words.
+type Relation string
+
+const (
+	Synonym    Relation = "synonym"    // happy → glad
+	Antonym    Relation = "antonym"    // happy → unhappy
+	Hypernym   Relation = "hypernym"   // dog → canine
+	Derivation Relation = "derivation" // happy → happiness
+)
+
+// pointerRelations maps WordNet pointer symbols onto relations; other pointers are ignored.
+// 形容詞の「類似」(&) は中心となる形容詞とその衛星形容詞をつなぐので類義語として扱う
+var pointerRelations = map[string]Relation{
+	"!":  Antonym,
+	"@":  Hypernym,
+	"@i": Hypernym,
+	"+":  Derivation,
+	"&":  Synonym,
+}
+
+// dataFiles are the WNDB data files and the part of speech of their synsets.
+var dataFiles = []struct {
+	name         string
+	partOfSpeech string
+}{
+	{"data.noun", "名詞"},
+	{"data.verb", "動詞"},
+	{"data.adj", "形容詞"},
+	{"data.adv", "副詞"},
+}
+
+// Link is a relation from Word to Related. Both are headwords as recorded by normalize.Fold.
+type Link struct {
+	Word         string
+	Relation     Relation
+	Related      string
+	PartOfSpeech string
+}
+
+// synset is a set of synonymous words and its pointers to other synsets.
+type synset struct {
+	partOfSpeech string
+	words        []string
+	pointers     []pointer
+}
+
+// pointer is a relation from a synset, or from one of its words, to another synset.
+type pointer struct {
+	relation Relation
+	target   string
+	// source and target are 1-based word numbers; 0 means the relation holds between the synsets
+	sourceWord int
+	targetWord int
+}
+
+// LoadDir reads the WNDB data files in dir and returns every link between words,
+// sorted by word, relation and related word.
+func LoadDir(dir string) ([]Link, error) {
+	synsets := map[string]*synset{}
+	for _, file := range dataFiles {
+		f, err := os.Open(filepath.Join(dir, file.name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open WordNet file: %w", err)
+		}
+		err = parseData(f, file.partOfSpeech, synsets)
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file.name, err)
+		}
+	}
+	return links(synsets), nil
+}
+
+// parseData reads the synsets of a WNDB data file into synsets, keyed by synsetKey.
+func parseData(r io.Reader, partOfSpeech string, synsets map[string]*synset) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		// ファイル先頭のライセンス文は空白2つで始まる
+		if line == "" || strings.HasPrefix(line, " ") {
+			continue
+		}
+		fields, _, _ := strings.Cut(line, "|")
+		key, s, err := parseSynset(strings.Fields(fields))
+		if err != nil {
+			return err
+		}
+		s.partOfSpeech = partOfSpeech
+		synsets[key] = s
+	}
+	return scanner.Err()
+}
+
+// parseSynset parses the fields of a data file line:
+// offset lex_filenum ss_type w_cnt (word lex_id)... p_cnt (symbol offset pos source/target)... [frames]
+func parseSynset(fields []string) (string, *synset, error) {
+	if len(fields) < 4 {
+		return "", nil, fmt.Errorf("malformed synset: %q", strings.Join(fields, " "))
+	}
+	key := synsetKey(fields[2], fields[0])
+
+	wordCount, err := strconv.ParseUint(fields[3], 16, 8)
+	if err != nil || len(fields) < 5+int(wordCount)*2 {
+		return "", nil, fmt.Errorf("malformed synset %s: bad word count", key)
+	}
+	s := &synset{}
+	for i := 0; i < int(wordCount); i++ {
+		s.words = append(s.words, lemma(fields[4+i*2]))
+	}
+
+	rest := fields[4+int(wordCount)*2:]
+	pointerCount, err := strconv.Atoi(rest[0])
+	if err != nil || len(rest) < 1+pointerCount*4 {
+		return "", nil, fmt.Errorf("malformed synset %s: bad pointer count", key)
+	}
+	for i := 0; i < pointerCount; i++ {
+		p := rest[1+i*4 : 5+i*4]
+		relation, ok := pointerRelations[p[0]]
+		if !ok {
+			continue
+		}
+		if len(p[3]) != 4 {
+			return "", nil, fmt.Errorf("malformed synset %s: bad pointer %q", key, p[3])
+		}
+		source, err1 := strconv.ParseUint(p[3][:2], 16, 8)
+		target, err2 := strconv.ParseUint(p[3][2:], 16, 8)
+		if err1 != nil || err2 != nil {
+			return "", nil, fmt.Errorf("malformed synset %s: bad pointer %q", key, p[3])
+		}
+		s.pointers = append(s.pointers, pointer{
+			relation:   relation,
+			target:     synsetKey(p[2], p[1]),
+			sourceWord: int(source),
+			targetWord: int(target),
+		})
+	}
+
+	return key, s, nil
+}
+
+// synsetKey identifies a synset by its part of speech and byte offset, e.g. "n:02084071".
+// 衛星形容詞 (s) は形容詞 (a) と同じファイルにあるので同じキー空間にまとめる
+func synsetKey(pos, offset string) string {
+	if pos == "s" {
+		pos = "a"
+	}
+	return pos + ":" + offset
+}
+
+// lemma turns a WordNet word such as "look_into" or "big(a)" into a headword.
+func lemma(word string) string {
+	// 形容詞の位置を表す (a) (p) (ip) を取り除く
+	if i := strings.IndexByte(word, '('); i > 0 {
+		word = word[:i]
+	}
+	return normalize.Fold(strings.ReplaceAll(word, "_", " "), "en")
+}
+
+// links expands the synsets into word-to-word links, dropping duplicates.
+// 同じ組み合わせが複数の品詞にある場合は最初の品詞を使う
+func links(synsets map[string]*synset) []Link {
+	seen := map[Link]bool{}
+	var result []Link
+	add := func(word string, relation Relation, related, partOfSpeech string) {
+		if word == related {
+			return
+		}
+		key := Link{Word: word, Relation: relation, Related: related}
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		key.PartOfSpeech = partOfSpeech
+		result = append(result, key)
+	}
+
+	// 品詞が決定的に選ばれるよう、synset をキー順にたどる
+	keys := make([]string, 0, len(synsets))
+	for key := range synsets {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		s := synsets[key]
+		for _, word := range s.words {
+			for _, synonym := range s.words {
+				add(word, Synonym, synonym, s.partOfSpeech)
+			}
+		}
+
+		for _, p := range s.pointers {
+			target, ok := synsets[p.target]
+			if !ok {
+				continue
+			}
+			for _, word := range pick(s.words, p.sourceWord) {
+				for _, related := range pick(target.words, p.targetWord) {
+					add(word, p.relation, related, s.partOfSpeech)
+				}
+			}
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.Word != b.Word {
+			return a.Word < b.Word
+		}
+		if a.Relation != b.Relation {
+			return a.Relation < b.Relation
+		}
+		return a.Related < b.Related
+	})
+	return result
+}
+
+// pick returns the nth (1-based) of words, or every word when n is 0.
+func pick(words []string, n int) []string {
+	switch {
+	case n == 0:
+		return words
+	case n <= len(words):
+		return words[n-1 : n]
+	default:
+		return nil
+	}
+}